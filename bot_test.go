@@ -0,0 +1,196 @@
+package disgolf_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGateway is a minimal Discord gateway, which sends Ready after identify, and then dispatches events.
+type fakeGateway struct {
+	*httptest.Server
+	events chan interface{}
+	calls  chan string
+}
+
+func newFakeGateway() *fakeGateway {
+	gw := &fakeGateway{events: make(chan interface{}, 8), calls: make(chan string, 8)}
+	upgrader := websocket.Upgrader{}
+	gw.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_ = conn.WriteJSON(map[string]interface{}{"op": 10, "d": map[string]interface{}{"heartbeat_interval": 45000}})
+		if _, _, err = conn.ReadMessage(); err != nil {
+			return
+		}
+
+		seq := 0
+		dispatch := func(typ string, data interface{}) error {
+			seq++
+			return conn.WriteJSON(map[string]interface{}{"op": 0, "s": seq, "t": typ, "d": data})
+		}
+		_ = dispatch("READY", map[string]interface{}{
+			"v":          10,
+			"session_id": "session",
+			"user":       map[string]interface{}{"id": "1", "username": "bot", "bot": true},
+			"guilds":     []interface{}{},
+		})
+
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		for {
+			select {
+			case event := <-gw.events:
+				_ = dispatch("INTERACTION_CREATE", event)
+			case <-closed:
+				return
+			}
+		}
+	}))
+	return gw
+}
+
+// RoundTrip implements http.RoundTripper interface. It serves the gateway url, and records other calls.
+func (gw *fakeGateway) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := `{}`
+	if strings.HasSuffix(req.URL.Path, "/gateway") {
+		data, _ := json.Marshal(map[string]string{"url": "ws" + strings.TrimPrefix(gw.URL, "http")})
+		body = string(data)
+	} else {
+		gw.calls <- req.Method + " " + req.URL.Path
+		if req.Method == http.MethodPut {
+			body = `[]`
+		}
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func newTestBot(t *testing.T, gw *fakeGateway) *disgolf.Bot {
+	bot, err := disgolf.New("token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot.Client = &http.Client{Transport: gw}
+	bot.ShouldReconnectOnError = false
+	return bot
+}
+
+func TestBot_Run(t *testing.T) {
+	gw := newFakeGateway()
+	defer gw.Close()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	bot := newTestBot(t, gw)
+	bot.SyncOnReady = true
+	bot.SyncGuilds = []string{"guild"}
+	bot.Router.Register(&disgolf.Command{
+		Name: "slow",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			close(started)
+			<-release
+		}),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Run(ctx) }()
+
+	select {
+	case call := <-gw.calls:
+		assert.Equal(t, "PUT /api/v"+discordgo.APIVersion+"/applications/1/guilds/guild/commands", call)
+	case <-time.After(5 * time.Second):
+		t.Fatal("commands were not synced")
+	}
+
+	gw.events <- &discordgo.Interaction{
+		ID:   "interaction",
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{Name: "slow"},
+	}
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler was not called")
+	}
+
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("bot has stopped before the handler finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("bot has not stopped")
+	}
+}
+
+func TestBot_RunShutdownTimeout(t *testing.T) {
+	gw := newFakeGateway()
+	defer gw.Close()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	bot := newTestBot(t, gw)
+	bot.ShutdownTimeout = 50 * time.Millisecond
+	bot.Router.Register(&disgolf.Command{
+		Name: "stuck",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			close(started)
+			<-release
+		}),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Run(ctx) }()
+
+	gw.events <- &discordgo.Interaction{
+		ID:   "interaction",
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{Name: "stuck"},
+	}
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler was not called")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, disgolf.ErrShutdownTimeout)
+	case <-time.After(5 * time.Second):
+		t.Fatal("bot has not stopped")
+	}
+}
@@ -0,0 +1,331 @@
+package disgolf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// A Check is a condition the invocation must satisfy to reach the command handler.
+// It returns a non-nil error, if the invocation does not satisfy the condition.
+// The error message is reported to the user, so it should be human readable.
+//
+// Check implements Middleware interface, so checks can be used in Command.CommonMiddlewares.
+type Check func(inv Invocation) error
+
+// DefaultCheckFailureResponse replies to the invocation, which failed a check, with the error message.
+func DefaultCheckFailureResponse(inv Invocation, err error) {
+	_ = inv.ReplyText(capitalize(err.Error()) + ".")
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// HandleInvocation implements Middleware interface.
+func (c Check) HandleInvocation(inv Invocation) {
+	if err := c(inv); err != nil {
+		response := DefaultCheckFailureResponse
+		if r := invocationRouter(inv); r != nil && r.CheckFailureResponse != nil {
+			response = r.CheckFailureResponse
+		}
+		response(inv, err)
+		return
+	}
+	inv.Next()
+}
+
+// HandleCommand implements Handler interface, which allows using the check in Command.Middlewares.
+func (c Check) HandleCommand(ctx *Ctx) { c.HandleInvocation(ctx) }
+
+// HandleMessageCommand implements MessageHandler interface, which allows using the check in Command.MessageMiddlewares.
+func (c Check) HandleMessageCommand(ctx *MessageCtx) { c.HandleInvocation(ctx) }
+
+// All combines checks into one. The checks are run in order, and the first error is returned.
+func All(checks ...Check) Check {
+	return func(inv Invocation) error {
+		for _, check := range checks {
+			if err := check(inv); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// GuildOnly rejects invocations outside of guilds.
+func GuildOnly(inv Invocation) error {
+	if inv.GuildID() == "" {
+		return ErrGuildOnly
+	}
+	return nil
+}
+
+// DMOnly rejects invocations outside of direct messages.
+func DMOnly(inv Invocation) error {
+	if inv.GuildID() != "" {
+		return ErrDMOnly
+	}
+	return nil
+}
+
+// NSFWOnly rejects invocations outside of NSFW channels. Threads inherit the flag from their parent channel.
+// Direct messages are not considered NSFW.
+func NSFWOnly(inv Invocation) error {
+	if inv.GuildID() == "" {
+		return ErrNSFWOnly
+	}
+
+	channel, err := fetchChannel(inv.DiscordSession(), inv.ChannelID())
+	if err != nil {
+		return fmt.Errorf("cannot retrieve the channel: %w", err)
+	}
+	if channel.IsThread() {
+		channel, err = fetchChannel(inv.DiscordSession(), channel.ParentID)
+		if err != nil {
+			return fmt.Errorf("cannot retrieve the channel: %w", err)
+		}
+	}
+
+	if !channel.NSFW {
+		return ErrNSFWOnly
+	}
+	return nil
+}
+
+func fetchChannel(s *discordgo.Session, id string) (*discordgo.Channel, error) {
+	if c, err := s.State.Channel(id); err == nil {
+		return c, nil
+	}
+	return s.Channel(id)
+}
+
+// MissingPermissionsError means that the invoking member or the bot lacks permissions in the channel.
+type MissingPermissionsError struct {
+	// Bot is true, if the permissions are missing for the bot.
+	Bot bool
+	// Missing permissions.
+	Permissions int64
+}
+
+func (e *MissingPermissionsError) Error() string {
+	subject := "you lack"
+	if e.Bot {
+		subject = "the bot lacks"
+	}
+	return fmt.Sprintf("%s the following permissions: %s", subject, strings.Join(PermissionNames(e.Permissions), ", "))
+}
+
+// RequireMemberPermissions rejects invocations by members without the specified permissions in the channel.
+// Invocations outside of guilds are rejected as well.
+func RequireMemberPermissions(permissions int64) Check {
+	return func(inv Invocation) error {
+		if err := GuildOnly(inv); err != nil {
+			return err
+		}
+		actual, err := inv.Permissions()
+		if err != nil {
+			return fmt.Errorf("cannot compute your permissions: %w", err)
+		}
+		if missing := missingPermissions(actual, permissions); missing != 0 {
+			return &MissingPermissionsError{Permissions: missing}
+		}
+		return nil
+	}
+}
+
+// RequireBotPermissions rejects invocations in channels where the bot does not have the specified permissions.
+// Invocations outside of guilds are let through.
+func RequireBotPermissions(permissions int64) Check {
+	return func(inv Invocation) error {
+		if inv.GuildID() == "" {
+			return nil
+		}
+		actual, err := inv.BotPermissions()
+		if err != nil {
+			return fmt.Errorf("cannot compute the bot permissions: %w", err)
+		}
+		if missing := missingPermissions(actual, permissions); missing != 0 {
+			return &MissingPermissionsError{Bot: true, Permissions: missing}
+		}
+		return nil
+	}
+}
+
+func missingPermissions(actual, required int64) int64 {
+	if actual&discordgo.PermissionAdministrator != 0 {
+		return 0
+	}
+	return required &^ actual
+}
+
+// MissingRolesError means that the invoking member does not have the required roles.
+type MissingRolesError struct {
+	// Roles the member must have.
+	Roles []string
+	// Any is true, if it is sufficient to have any of the roles.
+	Any bool
+}
+
+func (e *MissingRolesError) Error() string {
+	mentions := make([]string, len(e.Roles))
+	for i, role := range e.Roles {
+		mentions[i] = "<@&" + role + ">"
+	}
+	if e.Any {
+		return "you must have one of the following roles: " + strings.Join(mentions, ", ")
+	}
+	return "you lack the following roles: " + strings.Join(mentions, ", ")
+}
+
+func memberRoles(inv Invocation) map[string]bool {
+	roles := make(map[string]bool)
+	if member := inv.Member(); member != nil {
+		for _, role := range member.Roles {
+			roles[role] = true
+		}
+	}
+	return roles
+}
+
+// RequireRoles rejects invocations by members without all of the specified roles.
+// Invocations outside of guilds are rejected as well.
+func RequireRoles(roles ...string) Check {
+	return func(inv Invocation) error {
+		if err := GuildOnly(inv); err != nil {
+			return err
+		}
+		has := memberRoles(inv)
+		var missing []string
+		for _, role := range roles {
+			if !has[role] {
+				missing = append(missing, role)
+			}
+		}
+		if len(missing) != 0 {
+			return &MissingRolesError{Roles: missing}
+		}
+		return nil
+	}
+}
+
+// RequireAnyRole rejects invocations by members without at least one of the specified roles.
+// Invocations outside of guilds are rejected as well.
+func RequireAnyRole(roles ...string) Check {
+	return func(inv Invocation) error {
+		if err := GuildOnly(inv); err != nil {
+			return err
+		}
+		has := memberRoles(inv)
+		for _, role := range roles {
+			if has[role] {
+				return nil
+			}
+		}
+		return &MissingRolesError{Roles: roles, Any: true}
+	}
+}
+
+// OwnerOnly rejects invocations by users other than the specified ones.
+// If no users are specified, the owner of the application (or members of its team) are used,
+// which are fetched once from the API.
+func OwnerOnly(owners ...string) Check {
+	var mtx sync.Mutex
+	ids := make(map[string]bool, len(owners))
+	for _, owner := range owners {
+		ids[owner] = true
+	}
+
+	return func(inv Invocation) error {
+		mtx.Lock()
+		if len(ids) == 0 {
+			app, err := inv.DiscordSession().Application("@me")
+			if err != nil {
+				mtx.Unlock()
+				return fmt.Errorf("cannot retrieve the bot owners: %w", err)
+			}
+			if app.Owner != nil {
+				ids[app.Owner.ID] = true
+			}
+			if app.Team != nil {
+				for _, member := range app.Team.Members {
+					ids[member.User.ID] = true
+				}
+			}
+		}
+		author := inv.Author()
+		owner := author != nil && ids[author.ID]
+		mtx.Unlock()
+
+		if !owner {
+			return ErrOwnerOnly
+		}
+		return nil
+	}
+}
+
+var permissionNames = []struct {
+	permission int64
+	name       string
+}{
+	{discordgo.PermissionCreateInstantInvite, "Create Invite"},
+	{discordgo.PermissionKickMembers, "Kick Members"},
+	{discordgo.PermissionBanMembers, "Ban Members"},
+	{discordgo.PermissionAdministrator, "Administrator"},
+	{discordgo.PermissionManageChannels, "Manage Channels"},
+	{discordgo.PermissionManageServer, "Manage Server"},
+	{discordgo.PermissionAddReactions, "Add Reactions"},
+	{discordgo.PermissionViewAuditLogs, "View Audit Log"},
+	{discordgo.PermissionVoicePrioritySpeaker, "Priority Speaker"},
+	{discordgo.PermissionVoiceStreamVideo, "Video"},
+	{discordgo.PermissionViewChannel, "View Channel"},
+	{discordgo.PermissionSendMessages, "Send Messages"},
+	{discordgo.PermissionSendTTSMessages, "Send Text-to-Speech Messages"},
+	{discordgo.PermissionManageMessages, "Manage Messages"},
+	{discordgo.PermissionEmbedLinks, "Embed Links"},
+	{discordgo.PermissionAttachFiles, "Attach Files"},
+	{discordgo.PermissionReadMessageHistory, "Read Message History"},
+	{discordgo.PermissionMentionEveryone, "Mention Everyone"},
+	{discordgo.PermissionUseExternalEmojis, "Use External Emoji"},
+	{discordgo.PermissionViewGuildInsights, "View Server Insights"},
+	{discordgo.PermissionVoiceConnect, "Connect"},
+	{discordgo.PermissionVoiceSpeak, "Speak"},
+	{discordgo.PermissionVoiceMuteMembers, "Mute Members"},
+	{discordgo.PermissionVoiceDeafenMembers, "Deafen Members"},
+	{discordgo.PermissionVoiceMoveMembers, "Move Members"},
+	{discordgo.PermissionVoiceUseVAD, "Use Voice Activity"},
+	{discordgo.PermissionChangeNickname, "Change Nickname"},
+	{discordgo.PermissionManageNicknames, "Manage Nicknames"},
+	{discordgo.PermissionManageRoles, "Manage Roles"},
+	{discordgo.PermissionManageWebhooks, "Manage Webhooks"},
+	{discordgo.PermissionManageEmojis, "Manage Emojis and Stickers"},
+	{discordgo.PermissionUseSlashCommands, "Use Application Commands"},
+	{discordgo.PermissionVoiceRequestToSpeak, "Request to Speak"},
+	{discordgo.PermissionManageEvents, "Manage Events"},
+	{discordgo.PermissionManageThreads, "Manage Threads"},
+	{discordgo.PermissionCreatePublicThreads, "Create Public Threads"},
+	{discordgo.PermissionCreatePrivateThreads, "Create Private Threads"},
+	{discordgo.PermissionUseExternalStickers, "Use External Stickers"},
+	{discordgo.PermissionSendMessagesInThreads, "Send Messages in Threads"},
+	{discordgo.PermissionUseActivities, "Use Activities"},
+	{discordgo.PermissionModerateMembers, "Timeout Members"},
+}
+
+// PermissionNames returns human readable names of the permissions in the bit set.
+func PermissionNames(permissions int64) (names []string) {
+	for _, p := range permissionNames {
+		if permissions&p.permission != 0 {
+			names = append(names, p.name)
+			permissions &^= p.permission
+		}
+	}
+	if permissions != 0 {
+		names = append(names, fmt.Sprintf("Unknown (%#x)", permissions))
+	}
+	return
+}
@@ -0,0 +1,127 @@
+package disgolf_test
+
+import (
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func checksSession(t *testing.T) *discordgo.Session {
+	state := discordgo.NewState()
+	state.User = &discordgo.User{ID: "bot"}
+	err := state.GuildAdd(&discordgo.Guild{
+		ID:      "guild",
+		OwnerID: "owner",
+		Roles: []*discordgo.Role{
+			{ID: "guild", Permissions: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages},
+			{ID: "moderator", Permissions: discordgo.PermissionBanMembers},
+		},
+		Channels: []*discordgo.Channel{
+			{ID: "general", GuildID: "guild"},
+			{ID: "nsfw", GuildID: "guild", NSFW: true},
+		},
+		Members: []*discordgo.Member{
+			{GuildID: "guild", User: &discordgo.User{ID: "bot"}},
+			{GuildID: "guild", User: &discordgo.User{ID: "user"}},
+			{GuildID: "guild", User: &discordgo.User{ID: "mod"}, Roles: []string{"moderator"}},
+		},
+	})
+	assert.NoError(t, err)
+	return &discordgo.Session{State: state}
+}
+
+func TestChecks(t *testing.T) {
+	s := checksSession(t)
+	tests := []struct {
+		name    string
+		check   disgolf.Check
+		user    string
+		channel string
+		guild   string
+		err     error
+	}{
+		{"member permissions", disgolf.RequireMemberPermissions(discordgo.PermissionBanMembers), "mod", "general", "guild", nil},
+		{"missing member permissions", disgolf.RequireMemberPermissions(discordgo.PermissionBanMembers | discordgo.PermissionKickMembers), "user", "general", "guild",
+			&disgolf.MissingPermissionsError{Permissions: discordgo.PermissionBanMembers | discordgo.PermissionKickMembers}},
+		{"missing bot permissions", disgolf.RequireBotPermissions(discordgo.PermissionManageMessages), "user", "general", "guild",
+			&disgolf.MissingPermissionsError{Bot: true, Permissions: discordgo.PermissionManageMessages}},
+		{"roles", disgolf.RequireRoles("moderator"), "mod", "general", "guild", nil},
+		{"missing roles", disgolf.RequireAnyRole("moderator", "admin"), "user", "general", "guild",
+			&disgolf.MissingRolesError{Roles: []string{"moderator", "admin"}, Any: true}},
+		{"guild only", disgolf.GuildOnly, "user", "dm", "", disgolf.ErrGuildOnly},
+		{"dm only", disgolf.DMOnly, "user", "general", "guild", disgolf.ErrDMOnly},
+		{"nsfw", disgolf.NSFWOnly, "user", "nsfw", "guild", nil},
+		{"not nsfw", disgolf.NSFWOnly, "user", "general", "guild", disgolf.ErrNSFWOnly},
+		{"owner", disgolf.OwnerOnly("owner"), "owner", "general", "guild", nil},
+		{"not owner", disgolf.OwnerOnly("owner"), "user", "general", "guild", disgolf.ErrOwnerOnly},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var failure error
+			var handled bool
+			r := disgolf.NewRouter([]*disgolf.Command{{
+				Name:              "command",
+				CommonMiddlewares: []disgolf.Middleware{test.check},
+				MessageHandler:    disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) { handled = true }),
+			}})
+			r.CheckFailureResponse = func(inv disgolf.Invocation, err error) { failure = err }
+			var member *discordgo.Member
+			if test.guild != "" {
+				member, _ = s.State.Member(test.guild, test.user)
+			}
+			r.MakeMessageHandler(&disgolf.MessageHandlerConfig{Prefixes: []string{"!"}})(s, &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					Content:   "!command",
+					ChannelID: test.channel,
+					GuildID:   test.guild,
+					Author:    &discordgo.User{ID: test.user},
+					Member:    member,
+				},
+			})
+			assert.Equal(t, test.err, failure)
+			assert.Equal(t, test.err == nil, handled)
+		})
+	}
+}
+
+func TestPermissionNames(t *testing.T) {
+	assert.Equal(t, []string{"Kick Members", "Ban Members"}, disgolf.PermissionNames(discordgo.PermissionBanMembers|discordgo.PermissionKickMembers))
+}
+
+func TestCommand_AccessRules(t *testing.T) {
+	s := checksSession(t)
+	var failure error
+	permissions := int64(discordgo.PermissionBanMembers)
+	dm := false
+	command := &disgolf.Command{
+		Name:                     "ban",
+		DefaultMemberPermissions: &permissions,
+		DMPermission:             &dm,
+		MessageHandler:           disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {}),
+	}
+	assert.Equal(t, &permissions, command.ApplicationCommand().DefaultMemberPermissions)
+	assert.Equal(t, &dm, command.ApplicationCommand().DMPermission)
+
+	r := disgolf.NewRouter([]*disgolf.Command{command})
+	r.CheckFailureResponse = func(inv disgolf.Invocation, err error) { failure = err }
+	handler := r.MakeMessageHandler(&disgolf.MessageHandlerConfig{Prefixes: []string{"!"}})
+	invoke := func(user, guild string) error {
+		failure = nil
+		member, _ := s.State.Member(guild, user)
+		handler(s, &discordgo.MessageCreate{Message: &discordgo.Message{
+			Content:   "!ban",
+			ChannelID: "general",
+			GuildID:   guild,
+			Author:    &discordgo.User{ID: user},
+			Member:    member,
+		}})
+		return failure
+	}
+
+	assert.NoError(t, invoke("mod", "guild"))
+	assert.Equal(t, &disgolf.MissingPermissionsError{Permissions: permissions}, invoke("user", "guild"))
+	assert.Equal(t, disgolf.ErrGuildOnly, invoke("mod", ""))
+}
@@ -0,0 +1,411 @@
+package disgolf
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultCollectorTimeout is the time a collector waits for interactions or messages, if its timeout is not specified.
+const DefaultCollectorTimeout = time.Minute
+
+// collectorBuffer is the amount of collected items buffered for collectors without a limit.
+const collectorBuffer = 16
+
+// collector is the state shared by component and message collectors.
+type collector struct {
+	max       int
+	collected int
+
+	once sync.Once
+	done chan struct{}
+	err  error
+	ctx  context.Context
+}
+
+func newCollector(max int) collector {
+	return collector{max: max, done: make(chan struct{})}
+}
+
+func (c *collector) base() *collector { return c }
+
+func (c *collector) end(err error) {
+	c.once.Do(func() {
+		c.err = err
+		close(c.done)
+	})
+}
+
+func (c *collector) ended() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+	}
+	// NOTE: the watcher may not have noticed the end of the context yet, so it is checked here as well.
+	if c.ctx != nil && c.ctx.Err() != nil {
+		c.end(c.ctx.Err())
+		return true
+	}
+	return false
+}
+
+// count records a collected item, and stops the collector, once the maximal amount is collected.
+func (c *collector) count() {
+	c.collected++
+	if c.max != 0 && c.collected >= c.max {
+		c.end(ErrCollectorStopped)
+	}
+}
+
+// watch stops the collector on timeout or when the context ends, and removes it from the hub.
+func (c *collector) watch(hub *collectorHub, entry collectorEntry, ctx context.Context, timeout time.Duration) {
+	if timeout == 0 {
+		timeout = DefaultCollectorTimeout
+	}
+	c.ctx = ctx
+	hub.add(entry)
+	go func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			c.end(ErrCollectorTimeout)
+		case <-ctx.Done():
+			c.end(ctx.Err())
+		case <-c.done:
+		}
+		hub.remove(entry)
+	}()
+}
+
+// collectorEntry is a collector registered in a hub.
+type collectorEntry interface {
+	base() *collector
+	// offer passes the item to the collector. It returns false, if the collector does not accept the item.
+	offer(item interface{}) bool
+}
+
+// collectorHub is a registry of active collectors. Items are offered to the collectors in the order of registration.
+type collectorHub struct {
+	mtx  sync.Mutex
+	list []collectorEntry
+}
+
+func (h *collectorHub) add(entry collectorEntry) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.list = append(h.list, entry)
+}
+
+func (h *collectorHub) remove(entry collectorEntry) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for i, e := range h.list {
+		if e == entry {
+			h.list = append(h.list[:i], h.list[i+1:]...)
+			return
+		}
+	}
+}
+
+// dispatch passes the item to the first collector accepting it. It returns false, if no collector has accepted it.
+func (h *collectorHub) dispatch(item interface{}) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for _, entry := range h.list {
+		if !entry.base().ended() && entry.offer(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// ComponentCollectorConfig specifies, which component interactions are collected, and for how long.
+// Empty fields match any interaction.
+type ComponentCollectorConfig struct {
+	// MessageID is the id of the message the component belongs to.
+	MessageID string
+	// UserID is the id of the user, who interacts with the component.
+	UserID string
+	// CustomID is the custom id of the component.
+	CustomID string
+	// Filter is an additional filter. It is called after the fields above are matched.
+	Filter func(ctx *ComponentCtx) bool
+
+	// Timeout is the time the collector waits for interactions. Defaults to DefaultCollectorTimeout.
+	Timeout time.Duration
+	// Max is the maximal amount of interactions to collect. Zero means no limit.
+	Max int
+}
+
+func (cfg *ComponentCollectorConfig) match(ctx *ComponentCtx) bool {
+	switch {
+	case cfg.MessageID != "" && (ctx.Interaction.Message == nil || ctx.Interaction.Message.ID != cfg.MessageID):
+		return false
+	case cfg.UserID != "" && (ctx.Author() == nil || ctx.Author().ID != cfg.UserID):
+		return false
+	case cfg.CustomID != "" && ctx.Data.CustomID != cfg.CustomID:
+		return false
+	}
+	return cfg.Filter == nil || cfg.Filter(ctx)
+}
+
+// ComponentCollector receives component interactions matching its config, before they reach the component handlers of the router.
+// Collected interactions must be responded to by the receiver.
+//
+// The collector stops, when Max interactions are collected, the timeout expires, the context ends or Stop is called.
+type ComponentCollector struct {
+	collector
+	cfg ComponentCollectorConfig
+	ch  chan *ComponentCtx
+}
+
+func newComponentCollector(r *Router, c context.Context, cfg ComponentCollectorConfig) *ComponentCollector {
+	buffer := cfg.Max
+	if buffer == 0 {
+		buffer = collectorBuffer
+	}
+	collector := &ComponentCollector{
+		collector: newCollector(cfg.Max),
+		cfg:       cfg,
+		ch:        make(chan *ComponentCtx, buffer),
+	}
+	if r == nil {
+		collector.end(ErrCollectorUnavailable)
+		return collector
+	}
+	collector.watch(&r.componentCollectors, collector, c, cfg.Timeout)
+	return collector
+}
+
+func (c *ComponentCollector) offer(item interface{}) bool {
+	ctx, ok := item.(*ComponentCtx)
+	if !ok || !c.cfg.match(ctx) {
+		return false
+	}
+	select {
+	case c.ch <- ctx:
+		c.count()
+		return true
+	default:
+		// NOTE: the receiver does not keep up, the interaction is left to other collectors and handlers.
+		return false
+	}
+}
+
+// Next waits for the next collected interaction. Once the collector is stopped, and all the collected interactions are received,
+// it returns the reason: ErrCollectorStopped, ErrCollectorTimeout or the error of the context.
+func (c *ComponentCollector) Next() (*ComponentCtx, error) {
+	select {
+	case ctx := <-c.ch:
+		return ctx, nil
+	case <-c.done:
+		select {
+		case ctx := <-c.ch:
+			return ctx, nil
+		default:
+			return nil, c.err
+		}
+	}
+}
+
+// Collect waits until the collector stops, and returns all the collected interactions.
+// The error is only returned, if the context has ended.
+func (c *ComponentCollector) Collect() (collected []*ComponentCtx, err error) {
+	for {
+		ctx, err := c.Next()
+		if err != nil {
+			if err == ErrCollectorStopped || err == ErrCollectorTimeout {
+				err = nil
+			}
+			return collected, err
+		}
+		collected = append(collected, ctx)
+	}
+}
+
+// Stop stops the collector. Interactions collected before are still returned by Next.
+func (c *ComponentCollector) Stop() {
+	c.end(ErrCollectorStopped)
+}
+
+// MessageCollectorConfig specifies, which messages are collected, and for how long.
+type MessageCollectorConfig struct {
+	// ChannelID is the id of the channel the messages are sent to. Defaults to the channel of the invocation.
+	ChannelID string
+	// UserID is the id of the author of the messages. Defaults to the author of the invocation, unless AnyUser is set.
+	UserID string
+	// AnyUser makes the collector accept messages from any user.
+	AnyUser bool
+	// Filter is an additional filter. It is called after the channel and the author are matched.
+	Filter func(m *discordgo.Message) bool
+	// CancelKeyword stops the collector with ErrCollectorCancelled, when a matching message consists of it.
+	// It is case insensitive. The message itself is not collected.
+	CancelKeyword string
+
+	// Timeout is the time the collector waits for messages. Defaults to DefaultCollectorTimeout.
+	Timeout time.Duration
+	// Max is the maximal amount of messages to collect. Zero means no limit.
+	Max int
+}
+
+func (cfg *MessageCollectorConfig) match(m *discordgo.Message) bool {
+	switch {
+	case cfg.ChannelID != "" && m.ChannelID != cfg.ChannelID:
+		return false
+	case !cfg.AnyUser && cfg.UserID != "" && (m.Author == nil || m.Author.ID != cfg.UserID):
+		return false
+	}
+	return cfg.Filter == nil || cfg.Filter(m)
+}
+
+// MessageCollector receives messages matching its config, before they reach the message commands of the router.
+// Messages are received from the handler made by Router.MakeMessageHandler, so it must be registered.
+//
+// The collector stops, when Max messages are collected, the cancellation keyword is sent, the timeout expires,
+// the context ends or Stop is called.
+type MessageCollector struct {
+	collector
+	cfg MessageCollectorConfig
+	ch  chan *discordgo.Message
+}
+
+func newMessageCollector(r *Router, c context.Context, cfg MessageCollectorConfig) *MessageCollector {
+	buffer := cfg.Max
+	if buffer == 0 {
+		buffer = collectorBuffer
+	}
+	collector := &MessageCollector{
+		collector: newCollector(cfg.Max),
+		cfg:       cfg,
+		ch:        make(chan *discordgo.Message, buffer),
+	}
+	if r == nil {
+		collector.end(ErrCollectorUnavailable)
+		return collector
+	}
+	collector.watch(&r.messageCollectors, collector, c, cfg.Timeout)
+	return collector
+}
+
+func (c *MessageCollector) offer(item interface{}) bool {
+	m, ok := item.(*discordgo.Message)
+	if !ok || !c.cfg.match(m) {
+		return false
+	}
+	if c.cfg.CancelKeyword != "" && strings.EqualFold(strings.TrimSpace(m.Content), c.cfg.CancelKeyword) {
+		c.end(ErrCollectorCancelled)
+		return true
+	}
+	select {
+	case c.ch <- m:
+		c.count()
+		return true
+	default:
+		// NOTE: the receiver does not keep up, the message is left to other collectors and handlers.
+		return false
+	}
+}
+
+// Next waits for the next collected message. Once the collector is stopped, and all the collected messages are received,
+// it returns the reason: ErrCollectorStopped, ErrCollectorCancelled, ErrCollectorTimeout or the error of the context.
+func (c *MessageCollector) Next() (*discordgo.Message, error) {
+	select {
+	case m := <-c.ch:
+		return m, nil
+	case <-c.done:
+		select {
+		case m := <-c.ch:
+			return m, nil
+		default:
+			return nil, c.err
+		}
+	}
+}
+
+// Collect waits until the collector stops, and returns all the collected messages.
+// The error is only returned, if the collector was cancelled or the context has ended.
+func (c *MessageCollector) Collect() (collected []*discordgo.Message, err error) {
+	for {
+		m, err := c.Next()
+		if err != nil {
+			if err == ErrCollectorStopped || err == ErrCollectorTimeout {
+				err = nil
+			}
+			return collected, err
+		}
+		collected = append(collected, m)
+	}
+}
+
+// Stop stops the collector. Messages collected before are still returned by Next.
+func (c *MessageCollector) Stop() {
+	c.end(ErrCollectorStopped)
+}
+
+// messageCollectorDefaults fills the channel and the author of the invocation into the config.
+func messageCollectorDefaults(inv Invocation, cfg MessageCollectorConfig) MessageCollectorConfig {
+	if cfg.ChannelID == "" {
+		cfg.ChannelID = inv.ChannelID()
+	}
+	if cfg.UserID == "" && inv.Author() != nil {
+		cfg.UserID = inv.Author().ID
+	}
+	return cfg
+}
+
+// CollectComponents starts collecting component interactions. The collector stops, when the context of the invocation ends.
+func (ctx *Ctx) CollectComponents(cfg ComponentCollectorConfig) *ComponentCollector {
+	return newComponentCollector(ctx.router, ctx.Context(), cfg)
+}
+
+// AwaitComponent waits for a single component interaction matching the config.
+func (ctx *Ctx) AwaitComponent(cfg ComponentCollectorConfig) (*ComponentCtx, error) {
+	cfg.Max = 1
+	collector := ctx.CollectComponents(cfg)
+	defer collector.Stop()
+	return collector.Next()
+}
+
+// CollectMessages starts collecting messages in the channel of the invocation. The collector stops, when the context of the invocation ends.
+func (ctx *Ctx) CollectMessages(cfg MessageCollectorConfig) *MessageCollector {
+	return newMessageCollector(ctx.router, ctx.Context(), messageCollectorDefaults(ctx, cfg))
+}
+
+// AwaitMessage waits for a single message matching the config.
+func (ctx *Ctx) AwaitMessage(cfg MessageCollectorConfig) (*discordgo.Message, error) {
+	cfg.Max = 1
+	collector := ctx.CollectMessages(cfg)
+	defer collector.Stop()
+	return collector.Next()
+}
+
+// CollectComponents starts collecting component interactions. The collector stops, when the context of the invocation ends.
+func (ctx *MessageCtx) CollectComponents(cfg ComponentCollectorConfig) *ComponentCollector {
+	return newComponentCollector(ctx.router, ctx.Context(), cfg)
+}
+
+// AwaitComponent waits for a single component interaction matching the config.
+func (ctx *MessageCtx) AwaitComponent(cfg ComponentCollectorConfig) (*ComponentCtx, error) {
+	cfg.Max = 1
+	collector := ctx.CollectComponents(cfg)
+	defer collector.Stop()
+	return collector.Next()
+}
+
+// CollectMessages starts collecting messages in the channel of the invocation. The collector stops, when the context of the invocation ends.
+func (ctx *MessageCtx) CollectMessages(cfg MessageCollectorConfig) *MessageCollector {
+	return newMessageCollector(ctx.router, ctx.Context(), messageCollectorDefaults(ctx, cfg))
+}
+
+// AwaitMessage waits for a single message matching the config.
+func (ctx *MessageCtx) AwaitMessage(cfg MessageCollectorConfig) (*discordgo.Message, error) {
+	cfg.Max = 1
+	collector := ctx.CollectMessages(cfg)
+	defer collector.Stop()
+	return collector.Next()
+}
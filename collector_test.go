@@ -0,0 +1,205 @@
+package disgolf_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func componentInteraction(user, message, customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:    discordgo.InteractionMessageComponent,
+		User:    &discordgo.User{ID: user},
+		Message: &discordgo.Message{ID: message},
+		Data:    discordgo.MessageComponentInteractionData{CustomID: customID},
+	}}
+}
+
+func TestCtx_CollectComponents(t *testing.T) {
+	var handled []string
+	var collected []string
+	var collectErr error
+	ready := make(chan struct{})
+
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name: "confirm",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			collector := ctx.CollectComponents(disgolf.ComponentCollectorConfig{
+				MessageID: "message",
+				UserID:    "author",
+				Max:       2,
+			})
+			close(ready)
+			components, err := collector.Collect()
+			for _, c := range components {
+				collected = append(collected, c.Data.CustomID)
+			}
+			collectErr = err
+		}),
+	}})
+	r.RegisterComponent("confirm", disgolf.ComponentHandlerFunc(func(ctx *disgolf.ComponentCtx) {
+		handled = append(handled, ctx.Author().ID+" "+ctx.Data.CustomID)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			User: &discordgo.User{ID: "author"},
+			Data: discordgo.ApplicationCommandInteractionData{Name: "confirm"},
+		}})
+	}()
+	<-ready
+
+	r.HandleInteraction(nil, componentInteraction("other", "message", "confirm:yes"))
+	r.HandleInteraction(nil, componentInteraction("author", "another", "confirm:yes"))
+	r.HandleInteraction(nil, componentInteraction("author", "message", "confirm:no"))
+	r.HandleInteraction(nil, componentInteraction("author", "message", "confirm:yes"))
+	<-done
+	r.HandleInteraction(nil, componentInteraction("author", "message", "confirm:late"))
+
+	assert.NoError(t, collectErr)
+	assert.Equal(t, []string{"confirm:no", "confirm:yes"}, collected)
+	assert.Equal(t, []string{"other confirm:yes", "author confirm:yes", "author confirm:late"}, handled)
+}
+
+func TestCtx_CollectComponentsOverflow(t *testing.T) {
+	var handled int
+	ready, release := make(chan struct{}), make(chan struct{})
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name: "slow",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			collector := ctx.CollectComponents(disgolf.ComponentCollectorConfig{MessageID: "message"})
+			close(ready)
+			<-release
+			collector.Stop()
+		}),
+	}})
+	r.RegisterComponent("slow", disgolf.ComponentHandlerFunc(func(ctx *disgolf.ComponentCtx) { handled++ }))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{Name: "slow"},
+		}})
+	}()
+	<-ready
+
+	// NOTE: the collector is not received from, so interactions exceeding its buffer are passed to the handler.
+	for i := 0; i < 20; i++ {
+		r.HandleInteraction(nil, componentInteraction("user", "message", "slow"))
+	}
+	close(release)
+	<-done
+	assert.Equal(t, 4, handled)
+}
+
+func TestCtx_CollectComponentsAbandoned(t *testing.T) {
+	var collector *disgolf.ComponentCollector
+	var handled int
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name: "leak",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			collector = ctx.CollectComponents(disgolf.ComponentCollectorConfig{MessageID: "message"})
+		}),
+	}})
+	r.RegisterComponent("leak", disgolf.ComponentHandlerFunc(func(ctx *disgolf.ComponentCtx) { handled++ }))
+
+	r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{Name: "leak"},
+	}})
+	r.HandleInteraction(nil, componentInteraction("user", "message", "leak"))
+
+	assert.Equal(t, 1, handled)
+	_, err := collector.Next()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestCtx_AwaitComponent(t *testing.T) {
+	var errs []error
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name: "wait",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			_, err := ctx.AwaitComponent(disgolf.ComponentCollectorConfig{Timeout: 10 * time.Millisecond})
+			errs = append(errs, err)
+
+			c, cancel := context.WithCancel(ctx.Context())
+			cancel()
+			ctx.SetContext(c)
+			_, err = ctx.AwaitComponent(disgolf.ComponentCollectorConfig{})
+			errs = append(errs, err)
+		}),
+	}})
+	r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{Name: "wait"},
+	}})
+	assert.Equal(t, []error{disgolf.ErrCollectorTimeout, context.Canceled}, errs)
+
+	_, err := disgolf.NewCtx(nil, nil, &discordgo.Interaction{Type: discordgo.InteractionApplicationCommand, Data: discordgo.ApplicationCommandInteractionData{}}, nil, nil).
+		AwaitComponent(disgolf.ComponentCollectorConfig{})
+	assert.Equal(t, disgolf.ErrCollectorUnavailable, err)
+}
+
+func TestMessageCtx_CollectMessages(t *testing.T) {
+	var answers []string
+	var collectErr error
+	ready := make(chan struct{})
+	var commands []string
+
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name: "setup",
+			MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+				collector := ctx.CollectMessages(disgolf.MessageCollectorConfig{CancelKeyword: "cancel", Max: 3})
+				close(ready)
+				messages, err := collector.Collect()
+				for _, m := range messages {
+					answers = append(answers, m.Content)
+				}
+				collectErr = err
+			}),
+		},
+		{
+			Name: "ping",
+			MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+				commands = append(commands, ctx.Author().ID+" ping")
+			}),
+		},
+	})
+	handler := r.MakeMessageHandler(&disgolf.MessageHandlerConfig{Prefixes: []string{"!"}})
+	message := func(channel, author, content string) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{Message: &discordgo.Message{
+			ChannelID: channel,
+			Author:    &discordgo.User{ID: author},
+			Content:   content,
+		}}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler(nil, message("channel", "author", "!setup"))
+	}()
+	<-ready
+
+	handler(nil, message("channel", "author", "Fedor"))
+	handler(nil, message("channel", "other", "!ping"))
+	handler(nil, message("another", "author", "!ping"))
+	handler(nil, message("channel", "author", "!ping"))
+	handler(nil, message("channel", "author", "Cancel"))
+	<-done
+	handler(nil, message("channel", "author", "!ping"))
+
+	assert.Equal(t, disgolf.ErrCollectorCancelled, collectErr)
+	assert.Equal(t, []string{"Fedor", "!ping"}, answers)
+	assert.Equal(t, []string{"other ping", "author ping", "author ping"}, commands)
+}
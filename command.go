@@ -1,6 +1,8 @@
 package disgolf
 
 import (
+	"fmt"
+
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -28,14 +30,41 @@ func (f MessageHandlerFunc) HandleMessageCommand(ctx *MessageCtx) { f(ctx) }
 
 // Command represents a command.
 type Command struct {
-	Name               string
-	Description        string
-	Options            []*discordgo.ApplicationCommandOption
-	Type               discordgo.ApplicationCommandType
-	Handler            Handler
-	Middlewares        []Handler
-	MessageHandler     MessageHandler
-	MessageMiddlewares []MessageHandler
+	Name        string
+	Description string
+	Options     []*discordgo.ApplicationCommandOption
+	Type        discordgo.ApplicationCommandType
+	// Localized names of the command. Key is the locale, value is the name.
+	NameLocalizations map[discordgo.Locale]string
+	// Localized descriptions of the command. Key is the locale, value is the description.
+	DescriptionLocalizations map[discordgo.Locale]string
+	// Permissions the member must have to use the command. Zero value disables the command for everyone except administrators.
+	// For message commands it is enforced by the router.
+	//
+	// NOTE: it only has effect for top-level commands.
+	DefaultMemberPermissions *int64
+	// Whether the command is available in DMs. Defaults to true. For message commands it is enforced by the router.
+	//
+	// NOTE: it only has effect for top-level global commands.
+	DMPermission *bool
+	// Whether the command is age-restricted. For message commands it is enforced by the router.
+	//
+	// NOTE: it only has effect for top-level commands.
+	NSFW bool
+
+	Handler Handler
+	// AutocompleteHandler processes autocomplete interactions of the command. Middlewares are not applied to it.
+	AutocompleteHandler Handler
+	Middlewares         []Handler
+	MessageHandler      MessageHandler
+	MessageMiddlewares  []MessageHandler
+	// Middlewares applied to both slash and message invocations of the command.
+	// They are run before Middlewares and MessageMiddlewares.
+	CommonMiddlewares []Middleware
+	// Cooldown limits the rate of invocations of the command. It is applied after CommonMiddlewares.
+	Cooldown *Cooldown
+	// Concurrency limits the amount of simultaneously running invocations of the command. It is applied after Cooldown.
+	Concurrency *ConcurrencyLimit
 
 	// NOTE: nesting of more than 3 level has no effect
 	SubCommands *Router
@@ -43,13 +72,32 @@ type Command struct {
 	Custom interface{}
 }
 
+// IsContextMenu reports whether the command is a user or message command, which appears in the context menu.
+func (cmd Command) IsContextMenu() bool {
+	return cmd.Type == discordgo.UserApplicationCommand || cmd.Type == discordgo.MessageApplicationCommand
+}
+
 // ApplicationCommand converts Command to discordgo.ApplicationCommand.
+// Descriptions of context menu commands are omitted, since Discord rejects them.
 func (cmd Command) ApplicationCommand() *discordgo.ApplicationCommand {
 	applicationCommand := &discordgo.ApplicationCommand{
-		Name:        cmd.Name,
-		Description: cmd.Description,
-		Options:     cmd.Options,
-		Type:        cmd.Type,
+		Name:                     cmd.Name,
+		Description:              cmd.Description,
+		Options:                  cmd.Options,
+		Type:                     cmd.Type,
+		DefaultMemberPermissions: cmd.DefaultMemberPermissions,
+		DMPermission:             cmd.DMPermission,
+	}
+	if cmd.NameLocalizations != nil {
+		applicationCommand.NameLocalizations = &cmd.NameLocalizations
+	}
+	if cmd.IsContextMenu() {
+		applicationCommand.Description = ""
+		applicationCommand.Options = nil
+		return applicationCommand
+	}
+	if cmd.DescriptionLocalizations != nil {
+		applicationCommand.DescriptionLocalizations = &cmd.DescriptionLocalizations
 	}
 	for _, subcommand := range cmd.SubCommands.List() {
 		applicationCommand.Options = append(applicationCommand.Options, subcommand.ApplicationCommandOption())
@@ -57,6 +105,66 @@ func (cmd Command) ApplicationCommand() *discordgo.ApplicationCommand {
 	return applicationCommand
 }
 
+// ValidateCommand checks the name of the command and the structure Discord requires for its type:
+// user and message commands cannot have options or subcommands. Subcommands are validated as well.
+func ValidateCommand(cmd *Command) error {
+	if err := ValidateName(cmd.Name, cmd.Type); err != nil {
+		return err
+	}
+	if cmd.IsContextMenu() && (len(cmd.Options) != 0 || cmd.SubCommands.Count() != 0) {
+		return fmt.Errorf("command %q: %w", cmd.Name, ErrContextMenuOptions)
+	}
+	for _, subcommand := range cmd.SubCommands.List() {
+		if err := ValidateCommand(subcommand); err != nil {
+			return fmt.Errorf("command %q: %w", cmd.Name, err)
+		}
+	}
+	return nil
+}
+
+// applicationCommandPayload is discordgo.ApplicationCommand extended with the fields discordgo does not support yet.
+type applicationCommandPayload struct {
+	*discordgo.ApplicationCommand
+	NSFW bool `json:"nsfw,omitempty"`
+}
+
+// applicationCommandPayload converts Command to the payload sent to Discord.
+func (cmd Command) applicationCommandPayload() *applicationCommandPayload {
+	return &applicationCommandPayload{
+		ApplicationCommand: cmd.ApplicationCommand(),
+		NSFW:               cmd.NSFW,
+	}
+}
+
+// accessCheck returns a check, which enforces DefaultMemberPermissions, DMPermission and NSFW flags
+// the same way Discord does for slash commands. It returns nil, if the command has no access rules.
+func (cmd *Command) accessCheck() Check {
+	if cmd.DefaultMemberPermissions == nil && cmd.DMPermission == nil && !cmd.NSFW {
+		return nil
+	}
+	return func(inv Invocation) error {
+		if inv.GuildID() == "" {
+			if cmd.DMPermission != nil && !*cmd.DMPermission {
+				return ErrGuildOnly
+			}
+			return nil
+		}
+		if cmd.DefaultMemberPermissions != nil {
+			required := *cmd.DefaultMemberPermissions
+			if required == 0 {
+				required = discordgo.PermissionAdministrator
+			}
+			if err := RequireMemberPermissions(required)(inv); err != nil {
+				return err
+			}
+		}
+		if cmd.NSFW {
+			return NSFWOnly(inv)
+		}
+		return nil
+	}
+}
+
 // ApplicationCommandOption converts Command to discordgo.ApplicationCommandOption (subcommand).
 func (cmd Command) ApplicationCommandOption() *discordgo.ApplicationCommandOption {
 	applicationCommand := cmd.ApplicationCommand()
@@ -67,9 +175,11 @@ func (cmd Command) ApplicationCommandOption() *discordgo.ApplicationCommandOptio
 	}
 
 	return &discordgo.ApplicationCommandOption{
-		Name:        applicationCommand.Name,
-		Description: applicationCommand.Description,
-		Options:     applicationCommand.Options,
-		Type:        typ,
+		Name:                     applicationCommand.Name,
+		NameLocalizations:        cmd.NameLocalizations,
+		Description:              applicationCommand.Description,
+		DescriptionLocalizations: cmd.DescriptionLocalizations,
+		Options:                  applicationCommand.Options,
+		Type:                     typ,
 	}
 }
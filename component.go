@@ -0,0 +1,93 @@
+package disgolf
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// A ComponentHandler processes message component interactions.
+type ComponentHandler interface {
+	HandleComponent(ctx *ComponentCtx)
+}
+
+// ComponentHandlerFunc is a wrapper around ComponentHandler for functions
+type ComponentHandlerFunc func(ctx *ComponentCtx)
+
+// HandleComponent implements ComponentHandler interface and calls the function with provided context
+func (f ComponentHandlerFunc) HandleComponent(ctx *ComponentCtx) { f(ctx) }
+
+// ComponentCtx is a context provided to a component handler.
+type ComponentCtx struct {
+	*discordgo.Session `json:"-"`
+	Interaction        *discordgo.Interaction                    `json:"interaction"`
+	Data               discordgo.MessageComponentInteractionData `json:"data"`
+	// Arguments is the part of the custom id after the component name.
+	Arguments string `json:"arguments"`
+
+	respond     responder
+	responded   bool
+	respondedAt time.Time
+	failed      bool
+}
+
+// NewComponentCtx constructs ctx from given parameters.
+func NewComponentCtx(s *discordgo.Session, i *discordgo.Interaction) *ComponentCtx {
+	data := i.MessageComponentData()
+	_, arguments := splitCustomID(data.CustomID)
+	return &ComponentCtx{
+		Session:     s,
+		Interaction: i,
+		Data:        data,
+		Arguments:   arguments,
+	}
+}
+
+// Respond is a wrapper for ctx.Session.InteractionRespond
+func (ctx *ComponentCtx) Respond(response *discordgo.InteractionResponse) (err error) {
+	if ctx.respond != nil {
+		err = ctx.respond(response)
+	} else {
+		err = ctx.Session.InteractionRespond(ctx.Interaction, response)
+	}
+	if err == nil {
+		ctx.responded = true
+		ctx.respondedAt = time.Now()
+	} else {
+		ctx.failed = true
+	}
+	return err
+}
+
+// Responded reports whether the interaction was responded to through Respond.
+func (ctx *ComponentCtx) Responded() bool {
+	return ctx.responded
+}
+
+// Author returns the user who interacted with the component.
+func (ctx *ComponentCtx) Author() *discordgo.User {
+	if ctx.Interaction.Member != nil {
+		return ctx.Interaction.Member.User
+	}
+	return ctx.Interaction.User
+}
+
+// ComponentCustomID makes a custom id for a component from the name of its handler and the arguments.
+func ComponentCustomID(name, arguments string) string {
+	if arguments == "" {
+		return name
+	}
+	return name + ":" + arguments
+}
+
+// splitCustomID splits a custom id into the handler name and the arguments.
+func splitCustomID(customID string) (name, arguments string) {
+	if i := strings.IndexByte(customID, ':'); i != -1 {
+		return customID[:i], customID[i+1:]
+	}
+	return customID, ""
+}
+
+// responder sends the initial interaction response.
+type responder func(response *discordgo.InteractionResponse) error
@@ -0,0 +1,174 @@
+package disgolf
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConcurrencyMode specifies what happens to an invocation, when the concurrency limit is reached.
+type ConcurrencyMode int
+
+// Concurrency modes.
+const (
+	// ConcurrencyReject rejects the invocation.
+	ConcurrencyReject ConcurrencyMode = iota
+	// ConcurrencyQueue waits for one of the running invocations to finish.
+	// The invocation is rejected, if none finished before the timeout.
+	ConcurrencyQueue
+	// ConcurrencyCancelPrevious cancels the context of the oldest running invocation and waits for it to finish.
+	// The invocation is rejected, if the previous one did not finish before the timeout.
+	ConcurrencyCancelPrevious
+)
+
+// ConcurrencyLimit limits the amount of simultaneously running invocations of a command.
+// It can be set through Command.Concurrency, or used as a standalone middleware.
+//
+// NOTE: the invocation is considered running until the rest of the middleware chain returns.
+type ConcurrencyLimit struct {
+	// Bucket specifies how the invocations are grouped.
+	Bucket CooldownBucket
+	// Limit is the amount of invocations allowed to run simultaneously in each bucket. Defaults to 1.
+	Limit int
+	// Mode specifies how invocations exceeding the limit are handled.
+	Mode ConcurrencyMode
+	// Timeout for ConcurrencyQueue and ConcurrencyCancelPrevious modes. If zero, the invocation waits until its context is done.
+	Timeout time.Duration
+
+	// Key is used to identify the limit. If it is empty, the path of the invoked command is used.
+	Key string
+	// Response is called when the invocation is rejected. If nil, DefaultConcurrencyResponse is used.
+	Response func(inv Invocation)
+
+	mtx     sync.Mutex
+	buckets map[string]*concurrencyBucket
+}
+
+type concurrencyBucket struct {
+	slots   chan struct{}
+	running []*concurrencyRun
+	refs    int
+}
+
+type concurrencyRun struct {
+	cancel context.CancelFunc
+}
+
+// DefaultConcurrencyResponse replies to the invocation with a "please wait" message.
+func DefaultConcurrencyResponse(inv Invocation) {
+	_ = inv.ReplyText("This command is already running, please wait for it to finish.")
+}
+
+func (l *ConcurrencyLimit) acquireBucket(key string) *concurrencyBucket {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.buckets == nil {
+		l.buckets = make(map[string]*concurrencyBucket)
+	}
+	bucket, ok := l.buckets[key]
+	if !ok {
+		limit := l.Limit
+		if limit < 1 {
+			limit = 1
+		}
+		bucket = &concurrencyBucket{slots: make(chan struct{}, limit)}
+		l.buckets[key] = bucket
+	}
+	bucket.refs++
+	return bucket
+}
+
+func (l *ConcurrencyLimit) releaseBucket(key string, bucket *concurrencyBucket) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	bucket.refs--
+	if bucket.refs == 0 {
+		delete(l.buckets, key)
+	}
+}
+
+func (l *ConcurrencyLimit) cancelOldest(bucket *concurrencyBucket) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if len(bucket.running) != 0 {
+		bucket.running[0].cancel()
+	}
+}
+
+func (l *ConcurrencyLimit) wait(ctx context.Context, bucket *concurrencyBucket) bool {
+	var timeout <-chan time.Time
+	if l.Timeout > 0 {
+		timer := time.NewTimer(l.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case bucket.slots <- struct{}{}:
+		return true
+	case <-timeout:
+	case <-ctx.Done():
+	}
+	return false
+}
+
+// HandleInvocation implements Middleware interface.
+func (l *ConcurrencyLimit) HandleInvocation(inv Invocation) {
+	key := l.Key
+	if key == "" {
+		key = strings.Join(inv.CommandPath(), " ")
+	}
+	key += "/" + l.Bucket.Key(inv)
+
+	bucket := l.acquireBucket(key)
+	defer l.releaseBucket(key, bucket)
+
+	var acquired bool
+	select {
+	case bucket.slots <- struct{}{}:
+		acquired = true
+	default:
+		switch l.Mode {
+		case ConcurrencyQueue:
+			acquired = l.wait(inv.Context(), bucket)
+		case ConcurrencyCancelPrevious:
+			l.cancelOldest(bucket)
+			acquired = l.wait(inv.Context(), bucket)
+		}
+	}
+
+	if !acquired {
+		response := l.Response
+		if response == nil {
+			response = DefaultConcurrencyResponse
+		}
+		response(inv)
+		return
+	}
+	defer func() { <-bucket.slots }()
+
+	ctx, cancel := context.WithCancel(inv.Context())
+	defer cancel()
+	run := &concurrencyRun{cancel: cancel}
+
+	l.mtx.Lock()
+	bucket.running = append(bucket.running, run)
+	l.mtx.Unlock()
+	defer func() {
+		l.mtx.Lock()
+		for i, r := range bucket.running {
+			if r == run {
+				bucket.running = append(bucket.running[:i], bucket.running[i+1:]...)
+				break
+			}
+		}
+		l.mtx.Unlock()
+	}()
+
+	inv.SetContext(ctx)
+	inv.Next()
+}
@@ -0,0 +1,112 @@
+package disgolf_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func concurrencyRouter(limit *disgolf.ConcurrencyLimit, handler func(ctx *disgolf.MessageCtx)) func(user string) {
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name:           "transfer",
+			Concurrency:    limit,
+			MessageHandler: disgolf.MessageHandlerFunc(handler),
+		},
+	})
+	h := r.MakeMessageHandler(&disgolf.MessageHandlerConfig{Prefixes: []string{"!"}})
+	return func(user string) {
+		h(nil, &discordgo.MessageCreate{Message: &discordgo.Message{
+			Content: "!transfer",
+			Author:  &discordgo.User{ID: user},
+		}})
+	}
+}
+
+func TestConcurrencyLimit_Reject(t *testing.T) {
+	var rejected int
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	invoke := concurrencyRouter(&disgolf.ConcurrencyLimit{
+		Bucket:   disgolf.CooldownBucketUser,
+		Response: func(inv disgolf.Invocation) { rejected++ },
+	}, func(ctx *disgolf.MessageCtx) {
+		started <- struct{}{}
+		<-release
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); invoke("a") }()
+	go func() { defer wg.Done(); invoke("b") }()
+	<-started
+	<-started
+
+	invoke("a")
+	assert.Equal(t, 1, rejected)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestConcurrencyLimit_Queue(t *testing.T) {
+	var mtx sync.Mutex
+	var running, maxRunning int
+	invoke := concurrencyRouter(&disgolf.ConcurrencyLimit{
+		Bucket: disgolf.CooldownBucketGlobal,
+		Mode:   disgolf.ConcurrencyQueue,
+		Limit:  2,
+	}, func(ctx *disgolf.MessageCtx) {
+		mtx.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mtx.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mtx.Lock()
+		running--
+		mtx.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() { defer wg.Done(); invoke("a") }()
+	}
+	wg.Wait()
+	assert.Equal(t, 2, maxRunning)
+}
+
+func TestConcurrencyLimit_CancelPrevious(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	var calls int
+	invoke := concurrencyRouter(&disgolf.ConcurrencyLimit{
+		Bucket:  disgolf.CooldownBucketUser,
+		Mode:    disgolf.ConcurrencyCancelPrevious,
+		Timeout: time.Second,
+	}, func(ctx *disgolf.MessageCtx) {
+		calls++
+		if calls == 1 {
+			close(started)
+			<-ctx.Context().Done()
+			close(cancelled)
+		}
+	})
+
+	go invoke("a")
+	<-started
+	invoke("a")
+
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("previous invocation was not cancelled")
+	}
+	assert.Equal(t, 2, calls)
+}
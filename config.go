@@ -0,0 +1,335 @@
+package disgolf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/yaml.v3"
+)
+
+// An Option configures a Bot constructed by New.
+type Option func(b *Bot) error
+
+// WithIntents sets gateway intents of the session.
+func WithIntents(intents discordgo.Intent) Option {
+	return func(b *Bot) error {
+		b.Identify.Intents = intents
+		return nil
+	}
+}
+
+// StateConfig configures the state cache of the session.
+type StateConfig struct {
+	// Disabled disables the state cache.
+	Disabled bool `json:"disabled" yaml:"disabled" toml:"disabled"`
+	// MaxMessageCount is the amount of messages per channel stored in the state.
+	MaxMessageCount int `json:"max_message_count" yaml:"max_message_count" toml:"max_message_count"`
+	// IgnoreMembers and IgnorePresences disable tracking of members and presences.
+	IgnoreMembers   bool `json:"ignore_members" yaml:"ignore_members" toml:"ignore_members"`
+	IgnorePresences bool `json:"ignore_presences" yaml:"ignore_presences" toml:"ignore_presences"`
+}
+
+// WithState configures the state cache of the session.
+func WithState(cfg StateConfig) Option {
+	return func(b *Bot) error {
+		if cfg.MaxMessageCount < 0 {
+			return &ConfigError{Field: "state.max_message_count", Err: fmt.Errorf("must not be negative")}
+		}
+		b.StateEnabled = !cfg.Disabled
+		b.State.MaxMessageCount = cfg.MaxMessageCount
+		b.State.TrackMembers = !cfg.IgnoreMembers
+		b.State.TrackPresences = !cfg.IgnorePresences
+		return nil
+	}
+}
+
+// WithMessageHandlerConfig enables message commands with the configuration.
+func WithMessageHandlerConfig(cfg *MessageHandlerConfig) Option {
+	return func(b *Bot) error {
+		b.MessageHandlerConfig = cfg
+		return nil
+	}
+}
+
+// WithSync makes Bot.Run sync the commands once the session is ready.
+// Commands are synced to the guilds, or globally if no guilds are specified.
+func WithSync(guilds ...string) Option {
+	return func(b *Bot) error {
+		b.SyncOnReady = true
+		b.SyncGuilds = guilds
+		return nil
+	}
+}
+
+// WithOwners sets owners of the bot (see Bot.OwnerOnly).
+func WithOwners(owners ...string) Option {
+	return func(b *Bot) error {
+		b.Owners = owners
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the bot and its router.
+func WithLogger(l Logger) Option {
+	return func(b *Bot) error {
+		b.SetLogger(l)
+		return nil
+	}
+}
+
+// Config is a configuration of a Bot, which can be loaded from a file or environment variables.
+// The token is not applied by WithConfig, it is passed to New.
+type Config struct {
+	Token string `json:"token" yaml:"token" toml:"token"`
+	// Intents are names of gateway intents (e.g. guild_messages, message_content) or their numeric values.
+	Intents []string    `json:"intents" yaml:"intents" toml:"intents"`
+	State   StateConfig `json:"state" yaml:"state" toml:"state"`
+
+	// Prefixes of message commands. If empty and MentionPrefix is not set, message commands are disabled.
+	Prefixes          []string `json:"prefixes" yaml:"prefixes" toml:"prefixes"`
+	MentionPrefix     bool     `json:"mention_prefix" yaml:"mention_prefix" toml:"mention_prefix"`
+	ArgumentDelimiter string   `json:"argument_delimiter" yaml:"argument_delimiter" toml:"argument_delimiter"`
+
+	// Sync enables syncing of the commands once the session is ready.
+	Sync       bool     `json:"sync" yaml:"sync" toml:"sync"`
+	SyncGuilds []string `json:"sync_guilds" yaml:"sync_guilds" toml:"sync_guilds"`
+
+	Owners []string `json:"owners" yaml:"owners" toml:"owners"`
+}
+
+// WithConfig validates the configuration and applies it.
+func WithConfig(cfg *Config) Option {
+	return func(b *Bot) error {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+
+		options := []Option{WithState(cfg.State), WithOwners(cfg.Owners...)}
+		if len(cfg.Intents) != 0 {
+			intents, _ := ParseIntents(cfg.Intents)
+			options = append(options, WithIntents(intents))
+		}
+		if len(cfg.Prefixes) != 0 || cfg.MentionPrefix {
+			options = append(options, WithMessageHandlerConfig(&MessageHandlerConfig{
+				Prefixes:          cfg.Prefixes,
+				MentionPrefix:     cfg.MentionPrefix,
+				ArgumentDelimiter: cfg.ArgumentDelimiter,
+			}))
+		}
+		if cfg.Sync {
+			options = append(options, WithSync(cfg.SyncGuilds...))
+		}
+
+		for _, option := range options {
+			if err := option(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// intentNames maps names of the intents to their values.
+var intentNames = map[string]discordgo.Intent{
+	"guilds":                        discordgo.IntentGuilds,
+	"guild_members":                 discordgo.IntentGuildMembers,
+	"guild_bans":                    discordgo.IntentGuildBans,
+	"guild_emojis":                  discordgo.IntentGuildEmojis,
+	"guild_integrations":            discordgo.IntentGuildIntegrations,
+	"guild_webhooks":                discordgo.IntentGuildWebhooks,
+	"guild_invites":                 discordgo.IntentGuildInvites,
+	"guild_voice_states":            discordgo.IntentGuildVoiceStates,
+	"guild_presences":               discordgo.IntentGuildPresences,
+	"guild_messages":                discordgo.IntentGuildMessages,
+	"guild_message_reactions":       discordgo.IntentGuildMessageReactions,
+	"guild_message_typing":          discordgo.IntentGuildMessageTyping,
+	"direct_messages":               discordgo.IntentDirectMessages,
+	"direct_message_reactions":      discordgo.IntentDirectMessageReactions,
+	"direct_message_typing":         discordgo.IntentDirectMessageTyping,
+	"message_content":               discordgo.IntentMessageContent,
+	"guild_scheduled_events":        discordgo.IntentGuildScheduledEvents,
+	"auto_moderation_configuration": discordgo.IntentAutoModerationConfiguration,
+	"auto_moderation_execution":     discordgo.IntentAutoModerationExecution,
+	"all":                           discordgo.IntentsAll,
+	"all_without_privileged":        discordgo.IntentsAllWithoutPrivileged,
+}
+
+// ParseIntents combines intents specified by names (e.g. guild_messages) or numeric values.
+func ParseIntents(names []string) (intents discordgo.Intent, err error) {
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if intent, ok := intentNames[name]; ok {
+			intents |= intent
+			continue
+		}
+		value, err := strconv.ParseUint(name, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("unknown intent %q", name)
+		}
+		intents |= discordgo.Intent(value)
+	}
+	return intents, nil
+}
+
+var snowflakePattern = regexp.MustCompile(`^[0-9]{1,20}$`)
+
+// Validate checks the configuration.
+func (cfg *Config) Validate() error {
+	var errs ConfigErrors
+	if cfg.Token == "" {
+		errs = append(errs, &ConfigError{Field: "token", Err: fmt.Errorf("is required")})
+	}
+	if _, err := ParseIntents(cfg.Intents); err != nil {
+		errs = append(errs, &ConfigError{Field: "intents", Err: err})
+	}
+	if cfg.State.MaxMessageCount < 0 {
+		errs = append(errs, &ConfigError{Field: "state.max_message_count", Err: fmt.Errorf("must not be negative")})
+	}
+	for _, prefix := range cfg.Prefixes {
+		if strings.TrimSpace(prefix) == "" {
+			errs = append(errs, &ConfigError{Field: "prefixes", Err: fmt.Errorf("must not be blank")})
+			break
+		}
+	}
+	for _, guild := range cfg.SyncGuilds {
+		if !snowflakePattern.MatchString(guild) {
+			errs = append(errs, &ConfigError{Field: "sync_guilds", Err: fmt.Errorf("invalid id %q", guild)})
+		}
+	}
+	if len(cfg.SyncGuilds) != 0 && !cfg.Sync {
+		errs = append(errs, &ConfigError{Field: "sync_guilds", Err: fmt.Errorf("sync is disabled")})
+	}
+	for _, owner := range cfg.Owners {
+		if !snowflakePattern.MatchString(owner) {
+			errs = append(errs, &ConfigError{Field: "owners", Err: fmt.Errorf("invalid id %q", owner)})
+		}
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// ConfigError describes an invalid configuration value.
+type ConfigError struct {
+	// Field is the name of the field in configuration files.
+	Field string
+	Err   error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors is a list of configuration errors.
+type ConfigErrors []*ConfigError
+
+func (e ConfigErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// LoadConfigFile loads the configuration from the file. Supported formats are JSON (.json), YAML (.yaml, .yml) and TOML (.toml).
+// The configuration is not validated, so it can be completed by other sources (e.g. Config.LoadEnv) first.
+func LoadConfigFile(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		err = json.Unmarshal(data, cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return nil, fmt.Errorf("unknown config format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return cfg, nil
+}
+
+// LoadEnv overrides the configuration with environment variables. Variable names are field names of configuration files
+// in upper case with the prefix, e.g. BOT_TOKEN, BOT_SYNC_GUILDS and BOT_STATE_MAX_MESSAGE_COUNT for the "BOT_" prefix.
+// Lists are comma-separated.
+func (cfg *Config) LoadEnv(prefix string) error {
+	var errs ConfigErrors
+	lookup := func(field string) (string, bool) {
+		return os.LookupEnv(prefix + strings.ToUpper(strings.Replace(field, ".", "_", -1)))
+	}
+	str := func(field string, dst *string) {
+		if value, ok := lookup(field); ok {
+			*dst = value
+		}
+	}
+	list := func(field string, dst *[]string) {
+		if value, ok := lookup(field); ok {
+			*dst = nil
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					*dst = append(*dst, item)
+				}
+			}
+		}
+	}
+	boolean := func(field string, dst *bool) {
+		if value, ok := lookup(field); ok {
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, &ConfigError{Field: field, Err: fmt.Errorf("invalid boolean %q", value)})
+				return
+			}
+			*dst = v
+		}
+	}
+	integer := func(field string, dst *int) {
+		if value, ok := lookup(field); ok {
+			v, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, &ConfigError{Field: field, Err: fmt.Errorf("invalid integer %q", value)})
+				return
+			}
+			*dst = v
+		}
+	}
+
+	str("token", &cfg.Token)
+	list("intents", &cfg.Intents)
+	boolean("state.disabled", &cfg.State.Disabled)
+	integer("state.max_message_count", &cfg.State.MaxMessageCount)
+	boolean("state.ignore_members", &cfg.State.IgnoreMembers)
+	boolean("state.ignore_presences", &cfg.State.IgnorePresences)
+	list("prefixes", &cfg.Prefixes)
+	boolean("mention_prefix", &cfg.MentionPrefix)
+	str("argument_delimiter", &cfg.ArgumentDelimiter)
+	boolean("sync", &cfg.Sync)
+	list("sync_guilds", &cfg.SyncGuilds)
+	list("owners", &cfg.Owners)
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package disgolf_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, name, data string) string {
+	dir, err := ioutil.TempDir("", "disgolf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, name)
+	if err = ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	files := map[string]string{
+		"bot.yaml": `
+token: secret
+intents: [guilds, guild_messages, message_content]
+state:
+  max_message_count: 50
+prefixes: ["!"]
+sync: true
+sync_guilds: ["679281186975252480"]
+owners: ["1", "2"]
+`,
+		"bot.toml": `
+token = "secret"
+intents = ["guilds", "guild_messages", "message_content"]
+prefixes = ["!"]
+sync = true
+sync_guilds = ["679281186975252480"]
+owners = ["1", "2"]
+
+[state]
+max_message_count = 50
+`,
+	}
+
+	for name, data := range files {
+		cfg, err := disgolf.LoadConfigFile(writeConfig(t, name, data))
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+
+		bot, err := disgolf.New(cfg.Token, disgolf.WithConfig(cfg))
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		assert.Equal(t, "Bot secret", bot.Token, name)
+		assert.Equal(t, discordgo.IntentGuilds|discordgo.IntentGuildMessages|discordgo.IntentMessageContent, bot.Identify.Intents, name)
+		assert.Equal(t, 50, bot.State.MaxMessageCount, name)
+		assert.Equal(t, []string{"!"}, bot.MessageHandlerConfig.Prefixes, name)
+		assert.True(t, bot.SyncOnReady, name)
+		assert.Equal(t, []string{"679281186975252480"}, bot.SyncGuilds, name)
+		assert.Equal(t, []string{"1", "2"}, bot.Owners, name)
+	}
+}
+
+func TestConfig_LoadEnv(t *testing.T) {
+	env := map[string]string{
+		"BOT_TOKEN":                   "secret",
+		"BOT_INTENTS":                 "guilds, 512",
+		"BOT_STATE_MAX_MESSAGE_COUNT": "10",
+		"BOT_MENTION_PREFIX":          "true",
+		"BOT_OWNERS":                  "1,2",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	cfg := &disgolf.Config{Token: "file", Prefixes: []string{"!"}}
+	assert.NoError(t, cfg.LoadEnv("BOT_"))
+	assert.Equal(t, &disgolf.Config{
+		Token:         "secret",
+		Intents:       []string{"guilds", "512"},
+		State:         disgolf.StateConfig{MaxMessageCount: 10},
+		Prefixes:      []string{"!"},
+		MentionPrefix: true,
+		Owners:        []string{"1", "2"},
+	}, cfg)
+
+	intents, err := disgolf.ParseIntents(cfg.Intents)
+	assert.NoError(t, err)
+	assert.Equal(t, discordgo.IntentGuilds|discordgo.IntentGuildMessages, intents)
+
+	os.Setenv("BOT_SYNC", "maybe")
+	defer os.Unsetenv("BOT_SYNC")
+	var configErrs disgolf.ConfigErrors
+	if assert.True(t, errors.As(cfg.LoadEnv("BOT_"), &configErrs)) && assert.Len(t, configErrs, 1) {
+		assert.Equal(t, "sync", configErrs[0].Field)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := &disgolf.Config{
+		Intents:    []string{"guilds", "everything"},
+		State:      disgolf.StateConfig{MaxMessageCount: -1},
+		Prefixes:   []string{" "},
+		SyncGuilds: []string{"guild"},
+		Owners:     []string{"42"},
+	}
+
+	var errs disgolf.ConfigErrors
+	if assert.True(t, errors.As(cfg.Validate(), &errs)) {
+		var fields []string
+		for _, err := range errs {
+			fields = append(fields, err.Field)
+		}
+		assert.Equal(t, []string{"token", "intents", "state.max_message_count", "prefixes", "sync_guilds", "sync_guilds"}, fields)
+	}
+
+	_, err := disgolf.New("secret", disgolf.WithConfig(cfg))
+	assert.Error(t, err)
+}
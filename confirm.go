@@ -0,0 +1,137 @@
+package disgolf
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultConfirmationTimeout is the time a confirmation waits for the choice, if its timeout is not specified.
+const DefaultConfirmationTimeout = 30 * time.Second
+
+// ConfirmResult is the outcome of a confirmation.
+type ConfirmResult int
+
+// Outcomes of a confirmation. The zero value is ConfirmCancelled, so a result which was never set does not confirm anything.
+const (
+	// ConfirmCancelled means that the user has pressed the cancel button, or the context of the invocation has ended.
+	ConfirmCancelled ConfirmResult = iota
+	// ConfirmAccepted means that the user has pressed the confirm button.
+	ConfirmAccepted
+	// ConfirmTimedOut means that the user has not made a choice in time.
+	ConfirmTimedOut
+)
+
+func (r ConfirmResult) String() string {
+	switch r {
+	case ConfirmCancelled:
+		return "cancelled"
+	case ConfirmAccepted:
+		return "confirmed"
+	case ConfirmTimedOut:
+		return "timed out"
+	}
+	return "unknown"
+}
+
+// Confirmation is a prompt with confirm and cancel buttons. Only the author of the invocation can make the choice,
+// other users receive an ephemeral notice. Once the choice is made, the prompt is replaced with the outcome and the buttons are disabled.
+type Confirmation struct {
+	Prompt string
+	Embeds []*discordgo.MessageEmbed
+
+	// Labels of the buttons. Default to "Confirm" and "Cancel".
+	ConfirmLabel string
+	CancelLabel  string
+	// ConfirmStyle is the style of the confirm button. Defaults to discordgo.DangerButton.
+	ConfirmStyle discordgo.ButtonStyle
+
+	// Contents the prompt is replaced with for each outcome. Default to "Confirmed.", "Cancelled." and "Timed out.".
+	ConfirmedContent string
+	CancelledContent string
+	TimedOutContent  string
+
+	// Timeout is the time the confirmation waits for the choice. Defaults to DefaultConfirmationTimeout.
+	Timeout time.Duration
+}
+
+// Confirm asks the author of the invocation to confirm the prompt with the default settings. See Confirmation.Ask.
+func Confirm(inv Invocation, prompt string) (ConfirmResult, error) {
+	return (&Confirmation{Prompt: prompt}).Ask(inv)
+}
+
+// Ask sends the prompt in reply to the invocation, and waits for the choice of its author.
+// The error is returned, if the prompt cannot be sent, or the context of the invocation has ended.
+func (c *Confirmation) Ask(inv Invocation) (ConfirmResult, error) {
+	m, err := newInteractiveMessage(inv, "confirm")
+	if err != nil {
+		return ConfirmCancelled, err
+	}
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultConfirmationTimeout
+	}
+	collector := m.collect(inv.Context(), ComponentCollectorConfig{Timeout: timeout})
+	defer collector.Stop()
+
+	if err = m.send(c.data(m, c.Prompt, false)); err != nil {
+		return ConfirmCancelled, err
+	}
+
+	for {
+		ctx, err := collector.Next()
+		if err == ErrCollectorTimeout {
+			return ConfirmTimedOut, m.edit(c.data(m, stringOr(c.TimedOutContent, "Timed out."), true))
+		} else if err != nil {
+			_ = m.edit(c.data(m, stringOr(c.CancelledContent, "Cancelled."), true))
+			return ConfirmCancelled, err
+		}
+		if m.rejectForeignUser(ctx) {
+			continue
+		}
+
+		var result ConfirmResult
+		var content string
+		switch m.action(ctx) {
+		case "confirm":
+			result, content = ConfirmAccepted, stringOr(c.ConfirmedContent, "Confirmed.")
+		case "cancel":
+			result, content = ConfirmCancelled, stringOr(c.CancelledContent, "Cancelled.")
+		default:
+			continue
+		}
+		return result, ctx.Respond(&discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: c.data(m, content, true),
+		})
+	}
+}
+
+func (c *Confirmation) data(m *interactiveMessage, content string, disabled bool) *discordgo.InteractionResponseData {
+	style := c.ConfirmStyle
+	if style == 0 {
+		style = discordgo.DangerButton
+	}
+	embeds := c.Embeds
+	if embeds == nil {
+		embeds = []*discordgo.MessageEmbed{}
+	}
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: stringOr(c.ConfirmLabel, "Confirm"), Style: style, CustomID: m.customID("confirm")},
+			discordgo.Button{Label: stringOr(c.CancelLabel, "Cancel"), Style: discordgo.SecondaryButton, CustomID: m.customID("cancel")},
+		}},
+	}
+	if disabled {
+		components = disableComponents(components)
+	}
+	return &discordgo.InteractionResponseData{Content: content, Embeds: embeds, Components: components}
+}
+
+func stringOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package disgolf_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/FedorLap2006/disgolf/disgolftest"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirm(t *testing.T) {
+	results := make(chan disgolf.ConfirmResult, 1)
+	h := disgolftest.New(disgolf.NewRouter([]*disgolf.Command{{
+		Name: "purge",
+		MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+			result, err := disgolf.Confirm(ctx, "Delete all messages?")
+			assert.NoError(t, err)
+			results <- result
+		}),
+	}}))
+
+	go h.DispatchMessage(&discordgo.Message{ID: "200", Content: "!purge"})
+	var messages disgolftest.Calls
+	assert.Eventually(t, func() bool {
+		messages = h.Transport.Calls().Filter(http.MethodPost, "channels/*/messages")
+		return len(messages) == 1
+	}, time.Second, time.Millisecond)
+	if prompt := decodePayloads(t, messages)[0]; assert.Len(t, prompt.Components, 1) {
+		assert.Equal(t, "Delete all messages?", prompt.Content)
+		assert.Equal(t, "confirm:200:confirm", prompt.Components[0].Components[0].CustomID)
+	}
+
+	foreign := disgolftest.ComponentInteraction("confirm:200:confirm")
+	foreign.Member = &discordgo.Member{User: &discordgo.User{ID: "5"}}
+	foreign.GuildID = h.GuildID
+	h.Dispatch(foreign)
+	h.Dispatch(disgolftest.ComponentInteraction("confirm:200:confirm"))
+
+	select {
+	case result := <-results:
+		assert.Equal(t, disgolf.ConfirmAccepted, result)
+	case <-time.After(time.Second):
+		t.Fatal("confirmation has not finished")
+	}
+	responses := decodePayloads(t, h.Transport.Calls().Filter(http.MethodPost, "interactions/*/*/callback"))
+	if assert.Len(t, responses, 2) {
+		assert.Equal(t, disgolf.ErrAuthorOnly.Error(), responses[0].Data.Content)
+		assert.Equal(t, int(discordgo.InteractionResponseUpdateMessage), responses[1].Type)
+		assert.Equal(t, "Confirmed.", responses[1].Data.Content)
+	}
+}
+
+func TestConfirmResult(t *testing.T) {
+	var result disgolf.ConfirmResult
+	assert.Equal(t, disgolf.ConfirmCancelled, result)
+	assert.Equal(t, "cancelled", result.String())
+	assert.Equal(t, "confirmed", disgolf.ConfirmAccepted.String())
+	assert.Equal(t, "timed out", disgolf.ConfirmTimedOut.String())
+}
+
+func TestConfirmation_Timeout(t *testing.T) {
+	var result disgolf.ConfirmResult
+	h := disgolftest.New(disgolf.NewRouter([]*disgolf.Command{{
+		Name: "reset",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			var err error
+			result, err = (&disgolf.Confirmation{Prompt: "Reset?", Timeout: 10 * time.Millisecond}).Ask(ctx)
+			assert.NoError(t, err)
+		}),
+	}}))
+
+	calls := h.Command("reset")
+	assert.Equal(t, disgolf.ConfirmTimedOut, result)
+	if edits := decodePayloads(t, calls.Filter(http.MethodPatch, "webhooks/*/*/messages/@original")); assert.Len(t, edits, 1) {
+		assert.Equal(t, "Timed out.", edits[0].Content)
+		assert.True(t, edits[0].Components[0].Components[0].Disabled)
+	}
+}
@@ -1,7 +1,9 @@
 package disgolf
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -13,19 +15,201 @@ type OptionsMap = map[string]*discordgo.ApplicationCommandInteractionDataOption
 // and contains interaction and preprocessed options.
 type Ctx struct {
 	*discordgo.Session `json:"-"`
-	Caller      *Command `json:"caller"`
-	Interaction *discordgo.Interaction `json:"interaction"`
-	Options     OptionsMap `json:"options"`
-	OptionsRaw  []*discordgo.ApplicationCommandInteractionDataOption `json:"options_raw"`
+	Caller             *Command                                             `json:"caller"`
+	Interaction        *discordgo.Interaction                               `json:"interaction"`
+	Options            OptionsMap                                           `json:"options"`
+	OptionsRaw         []*discordgo.ApplicationCommandInteractionDataOption `json:"options_raw"`
 
 	remainingHandlers []Handler
+	responded         bool
+	respondedAt       time.Time
+	failed            bool
+	respond           responder
+	context           context.Context
+	translator        *Translator
+	router            *Router
+}
+
+// Translator implements Invocation interface. It returns the translator for the locale of the user,
+// falling back to the locale of the guild and the default one.
+func (ctx *Ctx) Translator() *Translator {
+	return ctx.translator
+}
+
+// T is a shortcut for ctx.Translator().T.
+func (ctx *Ctx) T(key string, vars Vars) string {
+	return ctx.translator.T(key, vars)
+}
+
+// N is a shortcut for ctx.Translator().N.
+func (ctx *Ctx) N(key string, n int, vars Vars) string {
+	return ctx.translator.N(key, n, vars)
+}
+
+// Context returns the context of the invocation. It is never nil, and is cancelled once the dispatch finishes.
+func (ctx *Ctx) Context() context.Context {
+	if ctx.context == nil {
+		return context.Background()
+	}
+	return ctx.context
+}
+
+// SetContext replaces the context of the invocation. Subsequent middlewares and the handler will observe the new context.
+func (ctx *Ctx) SetContext(c context.Context) {
+	ctx.context = c
 }
 
 // Respond is a wrapper for ctx.Session.InteractionRespond
-func (ctx *Ctx) Respond(response *discordgo.InteractionResponse) error {
-	return ctx.Session.InteractionRespond(ctx.Interaction, response)
+func (ctx *Ctx) Respond(response *discordgo.InteractionResponse) (err error) {
+	if ctx.respond != nil {
+		err = ctx.respond(response)
+	} else {
+		err = ctx.Session.InteractionRespond(ctx.Interaction, response)
+	}
+	if err == nil {
+		ctx.responded = true
+		ctx.respondedAt = time.Now()
+	} else {
+		ctx.failed = true
+	}
+	return err
+}
+
+// Responded reports whether the initial interaction response was sent through Respond.
+func (ctx *Ctx) Responded() bool {
+	return ctx.responded
+}
+
+// DiscordSession implements Invocation interface.
+func (ctx *Ctx) DiscordSession() *discordgo.Session {
+	return ctx.Session
+}
+
+// Command implements Invocation interface and returns ctx.Caller.
+func (ctx *Ctx) Command() *Command {
+	return ctx.Caller
+}
+
+// CommandPath implements Invocation interface. The path is taken from the interaction data.
+func (ctx *Ctx) CommandPath() []string {
+	data := ctx.Interaction.ApplicationCommandData()
+	path := []string{data.Name}
+	options := data.Options
+	for len(options) != 0 {
+		opt := options[0]
+		if opt.Type != discordgo.ApplicationCommandOptionSubCommand && opt.Type != discordgo.ApplicationCommandOptionSubCommandGroup {
+			break
+		}
+		path = append(path, opt.Name)
+		options = opt.Options
+	}
+	return path
+}
+
+// Author implements Invocation interface and returns the user who invoked the interaction.
+func (ctx *Ctx) Author() *discordgo.User {
+	if ctx.Interaction.Member != nil {
+		return ctx.Interaction.Member.User
+	}
+	return ctx.Interaction.User
+}
+
+// GuildID implements Invocation interface.
+func (ctx *Ctx) GuildID() string {
+	return ctx.Interaction.GuildID
+}
+
+// ChannelID implements Invocation interface.
+func (ctx *Ctx) ChannelID() string {
+	return ctx.Interaction.ChannelID
+}
+
+// Member implements Invocation interface. It is nil, if the interaction was invoked outside of a guild.
+func (ctx *Ctx) Member() *discordgo.Member {
+	return ctx.Interaction.Member
+}
+
+// TargetID returns the id of the user or message, which a context menu command was invoked on.
+func (ctx *Ctx) TargetID() string {
+	return ctx.Interaction.ApplicationCommandData().TargetID
+}
+
+// TargetUser returns the user, which a user command was invoked on. It is nil for other types of commands.
+func (ctx *Ctx) TargetUser() *discordgo.User {
+	data := ctx.Interaction.ApplicationCommandData()
+	if data.TargetID == "" || data.Resolved == nil {
+		return nil
+	}
+	return data.Resolved.Users[data.TargetID]
+}
+
+// TargetMember returns the member, which a user command was invoked on.
+// It is nil for other types of commands, and if the command was invoked outside of a guild.
+func (ctx *Ctx) TargetMember() *discordgo.Member {
+	data := ctx.Interaction.ApplicationCommandData()
+	if data.TargetID == "" || data.Resolved == nil {
+		return nil
+	}
+	member, ok := data.Resolved.Members[data.TargetID]
+	if !ok {
+		return nil
+	}
+	// NOTE: resolved members lack the user field, it is provided separately.
+	if member.User == nil {
+		member.User = data.Resolved.Users[data.TargetID]
+	}
+	if member.GuildID == "" {
+		member.GuildID = ctx.Interaction.GuildID
+	}
+	return member
+}
+
+// TargetMessage returns the message, which a message command was invoked on. It is nil for other types of commands.
+func (ctx *Ctx) TargetMessage() *discordgo.Message {
+	data := ctx.Interaction.ApplicationCommandData()
+	if data.TargetID == "" || data.Resolved == nil {
+		return nil
+	}
+	return data.Resolved.Messages[data.TargetID]
+}
+
+// Permissions implements Invocation interface and returns the permissions the invoking member has in the channel,
+// as provided by the interaction.
+func (ctx *Ctx) Permissions() (int64, error) {
+	if ctx.Interaction.Member == nil {
+		return 0, ErrGuildOnly
+	}
+	return ctx.Interaction.Member.Permissions, nil
 }
 
+// BotPermissions implements Invocation interface and returns the permissions the bot has in the channel,
+// as provided by the interaction.
+func (ctx *Ctx) BotPermissions() (int64, error) {
+	if ctx.Interaction.GuildID == "" {
+		return 0, ErrGuildOnly
+	}
+	return ctx.Interaction.AppPermissions, nil
+}
+
+// ReplyText implements Invocation interface. It responds to the interaction with a message,
+// or sends a followup message, if the interaction was already responded to.
+func (ctx *Ctx) ReplyText(content string) error {
+	if ctx.responded {
+		_, err := ctx.Session.FollowupMessageCreate(ctx.Interaction, true, &discordgo.WebhookParams{
+			Content: content,
+		})
+		if err != nil {
+			ctx.failed = true
+		}
+		return err
+	}
+	return ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: content},
+	})
+}
+
+// Next calls the next middleware / command handler.
 func (ctx *Ctx) Next() {
 	if len(ctx.remainingHandlers) == 0 {
 		return
@@ -41,7 +225,6 @@ func (ctx *Ctx) String() string {
 	return fmt.Sprintf(`caller: %s guild: %s options: %v`, ctx.Caller.Name, ctx.Interaction.GuildID, ctx.Options)
 }
 
-
 // NewCtx constructs ctx from given parameters.
 func NewCtx(s *discordgo.Session, caller *Command, i *discordgo.Interaction, parent *discordgo.ApplicationCommandInteractionDataOption, handlers []Handler) *Ctx {
 	options := i.ApplicationCommandData().Options
@@ -75,8 +258,113 @@ type MessageCtx struct {
 	Caller    *Command
 	Message   *discordgo.Message
 	Arguments []string
+	// Prefix the command was invoked with. It is trimmed from the message content.
+	Prefix string
 
 	remainingHandlers []MessageHandler
+	failed            bool
+	path              []string
+	context           context.Context
+	translator        *Translator
+	router            *Router
+}
+
+// Translator implements Invocation interface. It returns the translator for the locale of the guild
+// (as specified by I18n.GuildLocale), falling back to the default one.
+func (ctx *MessageCtx) Translator() *Translator {
+	return ctx.translator
+}
+
+// T is a shortcut for ctx.Translator().T.
+func (ctx *MessageCtx) T(key string, vars Vars) string {
+	return ctx.translator.T(key, vars)
+}
+
+// N is a shortcut for ctx.Translator().N.
+func (ctx *MessageCtx) N(key string, n int, vars Vars) string {
+	return ctx.translator.N(key, n, vars)
+}
+
+// Context returns the context of the invocation. It is never nil, and is cancelled once the dispatch finishes.
+func (ctx *MessageCtx) Context() context.Context {
+	if ctx.context == nil {
+		return context.Background()
+	}
+	return ctx.context
+}
+
+// SetContext replaces the context of the invocation. Subsequent middlewares and the handler will observe the new context.
+func (ctx *MessageCtx) SetContext(c context.Context) {
+	ctx.context = c
+}
+
+// DiscordSession implements Invocation interface.
+func (ctx *MessageCtx) DiscordSession() *discordgo.Session {
+	return ctx.Session
+}
+
+// Command implements Invocation interface and returns ctx.Caller.
+func (ctx *MessageCtx) Command() *Command {
+	return ctx.Caller
+}
+
+// CommandPath implements Invocation interface.
+func (ctx *MessageCtx) CommandPath() []string {
+	if ctx.path == nil {
+		return []string{ctx.Caller.Name}
+	}
+	return ctx.path
+}
+
+// Author implements Invocation interface and returns the author of the message.
+func (ctx *MessageCtx) Author() *discordgo.User {
+	return ctx.Message.Author
+}
+
+// GuildID implements Invocation interface.
+func (ctx *MessageCtx) GuildID() string {
+	return ctx.Message.GuildID
+}
+
+// ChannelID implements Invocation interface.
+func (ctx *MessageCtx) ChannelID() string {
+	return ctx.Message.ChannelID
+}
+
+// Member implements Invocation interface. It is nil, if the message was sent outside of a guild.
+//
+// NOTE: the member is partial, use Author to get the user.
+func (ctx *MessageCtx) Member() *discordgo.Member {
+	return ctx.Message.Member
+}
+
+// Permissions implements Invocation interface and returns the permissions the author has in the channel.
+// They are computed from the state, falling back to the REST API.
+func (ctx *MessageCtx) Permissions() (int64, error) {
+	if ctx.Message.GuildID == "" {
+		return 0, ErrGuildOnly
+	}
+	if ctx.Message.Member != nil {
+		if perms, err := ctx.Session.State.MessagePermissions(ctx.Message); err == nil {
+			return perms, nil
+		}
+	}
+	return ctx.Session.UserChannelPermissions(ctx.Message.Author.ID, ctx.Message.ChannelID)
+}
+
+// BotPermissions implements Invocation interface and returns the permissions the bot has in the channel.
+// They are computed from the state, falling back to the REST API.
+func (ctx *MessageCtx) BotPermissions() (int64, error) {
+	if ctx.Message.GuildID == "" {
+		return 0, ErrGuildOnly
+	}
+	return ctx.Session.UserChannelPermissions(ctx.Session.State.User.ID, ctx.Message.ChannelID)
+}
+
+// ReplyText implements Invocation interface. It is a shortcut for Reply without mentioning the author.
+func (ctx *MessageCtx) ReplyText(content string) error {
+	_, err := ctx.Reply(content, false)
+	return err
 }
 
 // Next calls the next middleware / command handler.
@@ -106,7 +394,11 @@ func (ctx *MessageCtx) ReplyComplex(message *discordgo.MessageSend, mention bool
 	// TODO: https://github.com/bwmarrin/discordgo/pull/1009
 	// message.AllowedMentions.RepliedUser = mention
 
-	return ctx.Session.ChannelMessageSendComplex(ctx.Message.ChannelID, message)
+	m, err := ctx.Session.ChannelMessageSendComplex(ctx.Message.ChannelID, message)
+	if err != nil {
+		ctx.failed = true
+	}
+	return m, err
 }
 
 // NewMessageCtx constructs context from a message.
@@ -120,3 +412,14 @@ func NewMessageCtx(s *discordgo.Session, caller *Command, m *discordgo.Message,
 		remainingHandlers: handlers,
 	}
 }
+
+// invocationRouter returns the router, which dispatched the invocation, or nil, if its context was not created by a router.
+func invocationRouter(inv Invocation) *Router {
+	switch ctx := inv.(type) {
+	case *Ctx:
+		return ctx.router
+	case *MessageCtx:
+		return ctx.router
+	}
+	return nil
+}
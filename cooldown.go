@@ -0,0 +1,192 @@
+package disgolf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CooldownBucket specifies how invocations are grouped when applying a cooldown.
+type CooldownBucket int
+
+// Cooldown buckets.
+const (
+	// CooldownBucketUser limits each user separately, regardless of where the command is invoked.
+	CooldownBucketUser CooldownBucket = iota
+	// CooldownBucketMember limits each user separately within every guild.
+	CooldownBucketMember
+	// CooldownBucketGuild limits each guild separately. Direct messages are limited per channel.
+	CooldownBucketGuild
+	// CooldownBucketChannel limits each channel separately.
+	CooldownBucketChannel
+	// CooldownBucketGlobal shares the limit between all invocations.
+	CooldownBucketGlobal
+)
+
+// String returns the name of the bucket.
+func (b CooldownBucket) String() string {
+	switch b {
+	case CooldownBucketUser:
+		return "user"
+	case CooldownBucketMember:
+		return "member"
+	case CooldownBucketGuild:
+		return "guild"
+	case CooldownBucketChannel:
+		return "channel"
+	case CooldownBucketGlobal:
+		return "global"
+	}
+	return fmt.Sprintf("CooldownBucket(%d)", int(b))
+}
+
+// Key returns the identifier of the bucket the invocation belongs to.
+func (b CooldownBucket) Key(inv Invocation) string {
+	var userID string
+	if author := inv.Author(); author != nil {
+		userID = author.ID
+	}
+
+	switch b {
+	case CooldownBucketUser:
+		return "user:" + userID
+	case CooldownBucketMember:
+		return "member:" + inv.GuildID() + ":" + userID
+	case CooldownBucketGuild:
+		if inv.GuildID() == "" {
+			return "channel:" + inv.ChannelID()
+		}
+		return "guild:" + inv.GuildID()
+	case CooldownBucketChannel:
+		return "channel:" + inv.ChannelID()
+	}
+	return "global"
+}
+
+// A CooldownStorage keeps track of invocations for cooldowns.
+type CooldownStorage interface {
+	// Take registers an invocation in the bucket identified by key, if less than rate invocations
+	// were registered in it during the window preceding now.
+	// Otherwise, it returns the time left until the next invocation is allowed.
+	Take(key string, rate int, window time.Duration, now time.Time) (retryAfter time.Duration, err error)
+}
+
+// DefaultCooldownStorage is used by cooldowns that have no storage specified.
+var DefaultCooldownStorage CooldownStorage = NewMemoryCooldownStorage()
+
+// MemoryCooldownStorage is an in-memory CooldownStorage implementation.
+type MemoryCooldownStorage struct {
+	mtx     sync.Mutex
+	buckets map[string]*memoryCooldownBucket
+	takes   int
+}
+
+type memoryCooldownBucket struct {
+	invocations []time.Time
+	window      time.Duration
+}
+
+// NewMemoryCooldownStorage constructs an empty MemoryCooldownStorage.
+func NewMemoryCooldownStorage() *MemoryCooldownStorage {
+	return &MemoryCooldownStorage{buckets: make(map[string]*memoryCooldownBucket)}
+}
+
+const memoryCooldownSweepInterval = 1024
+
+// Take implements CooldownStorage interface.
+func (s *MemoryCooldownStorage) Take(key string, rate int, window time.Duration, now time.Time) (time.Duration, error) {
+	if rate < 1 {
+		rate = 1
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.takes++
+	if s.takes%memoryCooldownSweepInterval == 0 {
+		s.sweep(now)
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryCooldownBucket{}
+		s.buckets[key] = bucket
+	}
+	bucket.window = window
+	bucket.expire(now)
+
+	if len(bucket.invocations) >= rate {
+		return bucket.invocations[len(bucket.invocations)-rate].Add(window).Sub(now), nil
+	}
+	bucket.invocations = append(bucket.invocations, now)
+	return 0, nil
+}
+
+// sweep removes buckets which had no invocations during their window.
+func (s *MemoryCooldownStorage) sweep(now time.Time) {
+	for key, bucket := range s.buckets {
+		if bucket.expire(now); len(bucket.invocations) == 0 {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func (b *memoryCooldownBucket) expire(now time.Time) {
+	i := 0
+	for i < len(b.invocations) && !b.invocations[i].Add(b.window).After(now) {
+		i++
+	}
+	b.invocations = b.invocations[i:]
+}
+
+// Cooldown limits the rate of command invocations.
+// It can be set through Command.Cooldown, or used as a standalone middleware.
+type Cooldown struct {
+	// Bucket specifies how the invocations are grouped.
+	Bucket CooldownBucket
+	// Rate is the amount of invocations allowed during the window.
+	Rate int
+	// Window is the duration of the window.
+	Window time.Duration
+
+	// Key is used to identify the cooldown in the storage.
+	// If it is empty, the path of the invoked command is used.
+	Key string
+	// Storage of the invocations. If nil, DefaultCooldownStorage is used.
+	Storage CooldownStorage
+	// Response is called when the invocation is rejected. If nil, DefaultCooldownResponse is used.
+	Response func(inv Invocation, retryAfter time.Duration)
+}
+
+// DefaultCooldownResponse replies to the invocation with a "try again" message.
+func DefaultCooldownResponse(inv Invocation, retryAfter time.Duration) {
+	_ = inv.ReplyText(fmt.Sprintf("You are on cooldown, try again in %ds.", int(math.Ceil(retryAfter.Seconds()))))
+}
+
+// HandleInvocation implements Middleware interface.
+func (c *Cooldown) HandleInvocation(inv Invocation) {
+	key := c.Key
+	if key == "" {
+		key = strings.Join(inv.CommandPath(), " ")
+	}
+	key += "/" + c.Bucket.Key(inv)
+
+	storage := c.Storage
+	if storage == nil {
+		storage = DefaultCooldownStorage
+	}
+	retryAfter, err := storage.Take(key, c.Rate, c.Window, time.Now())
+	// NOTE: storage failures should not make commands unusable, so the invocation is let through.
+	if err != nil || retryAfter <= 0 {
+		inv.Next()
+		return
+	}
+
+	response := c.Response
+	if response == nil {
+		response = DefaultCooldownResponse
+	}
+	response(inv, retryAfter)
+}
@@ -0,0 +1,66 @@
+package disgolf_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCooldownStorage_Take(t *testing.T) {
+	storage := disgolf.NewMemoryCooldownStorage()
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 2; i++ {
+		retryAfter, err := storage.Take("key", 2, time.Minute, now.Add(time.Duration(i)*time.Second))
+		assert.NoError(t, err)
+		assert.Zero(t, retryAfter)
+	}
+
+	retryAfter, err := storage.Take("key", 2, time.Minute, now.Add(10*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 50*time.Second, retryAfter)
+
+	retryAfter, _ = storage.Take("other", 2, time.Minute, now.Add(10*time.Second))
+	assert.Zero(t, retryAfter)
+
+	retryAfter, _ = storage.Take("key", 2, time.Minute, now.Add(time.Minute))
+	assert.Zero(t, retryAfter)
+}
+
+func TestCooldown(t *testing.T) {
+	var handled, rejected int
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name: "expensive",
+			Cooldown: &disgolf.Cooldown{
+				Bucket:  disgolf.CooldownBucketUser,
+				Rate:    1,
+				Window:  time.Hour,
+				Storage: disgolf.NewMemoryCooldownStorage(),
+				Response: func(inv disgolf.Invocation, retryAfter time.Duration) {
+					assert.True(t, retryAfter > 0)
+					rejected++
+				},
+			},
+			MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+				handled++
+			}),
+		},
+	})
+	handler := r.MakeMessageHandler(&disgolf.MessageHandlerConfig{Prefixes: []string{"!"}})
+	invoke := func(user string) {
+		handler(nil, &discordgo.MessageCreate{Message: &discordgo.Message{
+			Content: "!expensive",
+			Author:  &discordgo.User{ID: user},
+		}})
+	}
+
+	invoke("a")
+	invoke("a")
+	invoke("b")
+	assert.Equal(t, 2, handled)
+	assert.Equal(t, 1, rejected)
+}
@@ -1,25 +1,216 @@
 package disgolf
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/bwmarrin/discordgo"
 )
 
+// DefaultShutdownTimeout is the default time Bot.Run waits for running handlers to finish while shutting down.
+const DefaultShutdownTimeout = 10 * time.Second
+
 // A Bot wraps discordgo.Session with configuration and a router.
 type Bot struct {
 	*discordgo.Session
 
 	Router *Router
+	// Logger receives gateway connection events. If nil, nothing is logged.
+	// Use SetLogger to set the logger of both the bot and the router.
+	Logger Logger
+
+	// MessageHandlerConfig configures message commands handled by Run. If nil, message commands are not handled.
+	MessageHandlerConfig *MessageHandlerConfig
+	// SyncOnReady makes Run sync the commands once the session is ready.
+	SyncOnReady bool
+	// SyncGuilds are the guilds, which commands are synced to. If empty, commands are synced globally.
+	SyncGuilds []string
+	// ShutdownTimeout is the time Run waits for running handlers to finish. Defaults to DefaultShutdownTimeout.
+	ShutdownTimeout time.Duration
+	// Owners are ids of the users, who own the bot. See OwnerOnly.
+	Owners []string
+
+	mtx      sync.Mutex
+	draining bool
+	inflight sync.WaitGroup
 }
 
-// New constructs a Bot, from a authentication token.
-func New(token string) (*Bot, error) {
+// New constructs a Bot, from a authentication token and options.
+func New(token string, options ...Option) (*Bot, error) {
 	session, err := discordgo.New("Bot " + token)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Bot{
+	bot := &Bot{
 		Session: session,
 		Router:  NewRouter(nil),
-	}, nil
+	}
+	for _, option := range options {
+		if err = option(bot); err != nil {
+			return nil, err
+		}
+	}
+	session.AddHandler(bot.logConnection)
+	return bot, nil
+}
+
+// OwnerOnly is a check, which rejects invocations by users other than Owners of the bot.
+// If the bot has no owners, the owner of the application is used (see OwnerOnly function).
+func (b *Bot) OwnerOnly() Check {
+	return OwnerOnly(b.Owners...)
+}
+
+// SetLogger sets the logger of the bot and its router.
+func (b *Bot) SetLogger(l Logger) {
+	b.Logger = l
+	b.Router.Logger = l
+}
+
+func (b *Bot) logger() Logger {
+	if b.Logger == nil {
+		return nopLogger{}
+	}
+	return b.Logger
+}
+
+func (b *Bot) logConnection(s *discordgo.Session, event interface{}) {
+	l := b.Logger
+	if l == nil {
+		return
+	}
+
+	switch event := event.(type) {
+	case *discordgo.Connect:
+		l.Info("connected to the gateway")
+	case *discordgo.Disconnect:
+		l.Warn("disconnected from the gateway")
+	case *discordgo.Ready:
+		l.Info("session is ready", "user", event.User.ID, "guilds", len(event.Guilds), "session", event.SessionID)
+	case *discordgo.Resumed:
+		l.Info("session resumed")
+	}
+}
+
+// track registers an in-flight handler. It returns false, if the bot is shutting down.
+func (b *Bot) track() bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.draining {
+		return false
+	}
+	b.inflight.Add(1)
+	return true
+}
+
+// Run opens the session, registers the router handlers and blocks until the context is done.
+// If SyncOnReady is set, the commands are synced once the session is ready, and failed sync stops the bot.
+//
+// On shutdown, new invocations are ignored, and running handlers are waited for up to ShutdownTimeout,
+// after which the session is closed. Run returns nil, if the bot was stopped by the context.
+func (b *Bot) Run(ctx context.Context) (err error) {
+	b.mtx.Lock()
+	b.draining = false
+	b.mtx.Unlock()
+
+	var messageHandler func(*discordgo.Session, *discordgo.MessageCreate)
+	if b.MessageHandlerConfig != nil {
+		messageHandler = b.Router.MakeMessageHandler(b.MessageHandlerConfig)
+	}
+
+	syncErr := make(chan error, 1)
+	var syncOnce sync.Once
+	removers := []func(){
+		b.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+			if !b.track() {
+				return
+			}
+			defer b.inflight.Done()
+			b.Router.HandleInteraction(s, i)
+		}),
+		b.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+			if messageHandler == nil || !b.track() {
+				return
+			}
+			defer b.inflight.Done()
+			messageHandler(s, m)
+		}),
+		b.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
+			if !b.SyncOnReady {
+				return
+			}
+			syncOnce.Do(func() { syncErr <- b.sync(r.User.ID) })
+		}),
+	}
+	defer func() {
+		for _, remove := range removers {
+			remove()
+		}
+	}()
+
+	if err = b.Open(); err != nil {
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case err = <-syncErr:
+		if err != nil {
+			err = fmt.Errorf("failed to sync commands: %w", err)
+		} else {
+			<-ctx.Done()
+		}
+	}
+
+	if shutdownErr := b.shutdown(); err == nil {
+		err = shutdownErr
+	}
+	return err
+}
+
+func (b *Bot) sync(application string) error {
+	if len(b.SyncGuilds) == 0 {
+		return b.Router.Sync(b.Session, application, "")
+	}
+	for _, guild := range b.SyncGuilds {
+		if err := b.Router.Sync(b.Session, application, guild); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shutdown stops accepting new invocations, waits for running handlers and closes the session.
+func (b *Bot) shutdown() error {
+	b.mtx.Lock()
+	b.draining = true
+	b.mtx.Unlock()
+
+	timeout := b.ShutdownTimeout
+	if timeout == 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	l := b.logger()
+	l.Info("shutting down, waiting for running handlers", "timeout", timeout)
+
+	done := make(chan struct{})
+	go func() {
+		b.inflight.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		l.Warn("handlers have not finished in time", "timeout", timeout)
+		err = ErrShutdownTimeout
+	}
+
+	if closeErr := b.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close session: %w", closeErr)
+	}
+	return err
 }
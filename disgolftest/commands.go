@@ -0,0 +1,444 @@
+package disgolftest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxCommands is the maximal amount of chat input commands in a single scope.
+const maxCommands = 100
+
+// Failure is a failure injected into CommandsServer.
+type Failure struct {
+	// Method of the requests to fail. Empty method matches all methods.
+	Method string
+	// Path pattern of the requests to fail, relative to discordgo.EndpointAPI. "*" matches a single path segment.
+	// Empty pattern matches all paths.
+	Path string
+	// Status of the response. Defaults to 500.
+	Status int
+	// Code and Message of the Discord error returned in the response.
+	Code    int
+	Message string
+	// RetryAfter makes the failure a rate limit, that should be retried after the duration.
+	RetryAfter time.Duration
+	// Times is the amount of requests to fail. Zero means forever.
+	Times int
+}
+
+func (f *Failure) matches(method, path string) bool {
+	return (f.Method == "" || f.Method == method) && (f.Path == "" || matchPath(f.Path, path))
+}
+
+// CommandsServer is an in-process fake of the application commands REST API.
+// It serves both global and guild commands (list, get, create, edit, delete, bulk overwrite) and their permissions.
+//
+// It can be plugged into discordgo.Session as an HTTP transport (see Transport and Session),
+// or served through httptest.Server with endpoints overridden by UseEndpoints.
+type CommandsServer struct {
+	mtx         sync.Mutex
+	commands    map[string][]*discordgo.ApplicationCommand
+	permissions map[string]*discordgo.GuildApplicationCommandPermissions
+	failures    []*Failure
+	requests    Calls
+	lastID      uint64
+}
+
+// NewCommandsServer constructs an empty CommandsServer.
+func NewCommandsServer() *CommandsServer {
+	return &CommandsServer{
+		commands:    make(map[string][]*discordgo.ApplicationCommand),
+		permissions: make(map[string]*discordgo.GuildApplicationCommandPermissions),
+	}
+}
+
+func scopeKey(application, guild string) string {
+	return application + "/" + guild
+}
+
+// Commands returns commands registered in the scope. Empty guild means global commands.
+func (srv *CommandsServer) Commands(application, guild string) []*discordgo.ApplicationCommand {
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+	return append([]*discordgo.ApplicationCommand(nil), srv.commands[scopeKey(application, guild)]...)
+}
+
+// SetCommands replaces commands registered in the scope. Missing ids are generated.
+func (srv *CommandsServer) SetCommands(application, guild string, commands []*discordgo.ApplicationCommand) {
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+	for _, cmd := range commands {
+		srv.fill(cmd, application, guild)
+	}
+	srv.commands[scopeKey(application, guild)] = commands
+}
+
+// Permissions returns permissions of the guild command.
+func (srv *CommandsServer) Permissions(application, guild, command string) *discordgo.GuildApplicationCommandPermissions {
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+	return srv.permissions[scopeKey(application, guild)+"/"+command]
+}
+
+// Requests returns all requests received by the server.
+func (srv *CommandsServer) Requests() Calls {
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+	return append(Calls(nil), srv.requests...)
+}
+
+// Fail injects a failure. Failures are matched in the order of injection.
+func (srv *CommandsServer) Fail(f Failure) {
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+	srv.failures = append(srv.failures, &f)
+}
+
+// RateLimit makes the server respond with "429 Too Many Requests" to the requests matching method and path the specified amount of times.
+func (srv *CommandsServer) RateLimit(method, path string, retryAfter time.Duration, times int) {
+	srv.Fail(Failure{
+		Method:     method,
+		Path:       path,
+		Status:     http.StatusTooManyRequests,
+		Message:    "You are being rate limited.",
+		RetryAfter: retryAfter,
+		Times:      times,
+	})
+}
+
+// RoundTrip implements http.RoundTripper interface, which allows using the server as a transport of discordgo.Session.
+func (srv *CommandsServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// Session constructs a session, which uses the server as its transport.
+func (srv *CommandsServer) Session() *discordgo.Session {
+	s, _ := discordgo.New("Bot test")
+	s.Client = &http.Client{Transport: srv}
+	return s
+}
+
+// UseEndpoints overrides discordgo application endpoints to point to the base URL (e.g. of httptest.Server),
+// and returns a function restoring them.
+//
+// NOTE: the endpoints are global, so tests using it must not run in parallel.
+func UseEndpoints(base string) (restore func()) {
+	api, applications := discordgo.EndpointAPI, discordgo.EndpointApplications
+	discordgo.EndpointAPI = strings.TrimSuffix(base, "/") + apiPath
+	discordgo.EndpointApplications = discordgo.EndpointAPI + "applications"
+	return func() {
+		discordgo.EndpointAPI, discordgo.EndpointApplications = api, applications
+	}
+}
+
+type discordError struct {
+	Code       int     `json:"code"`
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after,omitempty"`
+	Global     bool    `json:"global,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, discordError{Code: code, Message: message})
+}
+
+// ServeHTTP implements http.Handler interface.
+func (srv *CommandsServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, 0, err.Error())
+		return
+	}
+	path := req.URL.Path
+	if i := strings.Index(path, "/applications/"); i != -1 {
+		path = path[i+1:]
+	}
+
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+	srv.requests = append(srv.requests, Call{Method: req.Method, Path: path, Body: body})
+
+	if srv.fail(w, req.Method, path) {
+		return
+	}
+
+	segments := strings.Split(path, "/")
+	if len(segments) < 3 || segments[0] != "applications" {
+		writeError(w, http.StatusNotFound, 0, "404: Not Found")
+		return
+	}
+	application, guild, rest := segments[1], "", segments[2:]
+	if rest[0] == "guilds" && len(rest) >= 3 {
+		guild, rest = rest[1], rest[2:]
+	}
+	if rest[0] != "commands" {
+		writeError(w, http.StatusNotFound, 0, "404: Not Found")
+		return
+	}
+	rest = rest[1:]
+
+	switch {
+	case len(rest) == 0:
+		srv.serveCommands(w, req.Method, application, guild, body)
+	case len(rest) == 1 && rest[0] == "permissions" && guild != "":
+		srv.serveGuildPermissions(w, req.Method, application, guild, body)
+	case len(rest) == 1:
+		srv.serveCommand(w, req.Method, application, guild, rest[0], body)
+	case len(rest) == 2 && rest[1] == "permissions" && guild != "":
+		srv.serveCommandPermissions(w, req.Method, application, guild, rest[0], body)
+	default:
+		writeError(w, http.StatusNotFound, 0, "404: Not Found")
+	}
+}
+
+func (srv *CommandsServer) fail(w http.ResponseWriter, method, path string) bool {
+	for i, f := range srv.failures {
+		if !f.matches(method, path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				srv.failures = append(srv.failures[:i], srv.failures[i+1:]...)
+			}
+		}
+
+		status := f.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		if f.RetryAfter != 0 {
+			w.Header().Set("Retry-After", fmt.Sprint(f.RetryAfter.Seconds()))
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset-After", fmt.Sprint(f.RetryAfter.Seconds()))
+		}
+		writeJSON(w, status, discordError{Code: f.Code, Message: f.Message, RetryAfter: f.RetryAfter.Seconds()})
+		return true
+	}
+	return false
+}
+
+func (srv *CommandsServer) nextID() string {
+	srv.lastID++
+	return fmt.Sprint(2000000000000000000 + srv.lastID)
+}
+
+func (srv *CommandsServer) fill(cmd *discordgo.ApplicationCommand, application, guild string) {
+	if cmd.ID == "" {
+		cmd.ID = srv.nextID()
+	}
+	cmd.ApplicationID = application
+	cmd.GuildID = guild
+	cmd.Version = srv.nextID()
+}
+
+// validateCommand validates the command and sets the default type.
+func validateCommand(cmd *discordgo.ApplicationCommand) string {
+	if cmd.Type == 0 {
+		cmd.Type = discordgo.ChatApplicationCommand
+	}
+	if cmd.Name == "" || len(cmd.Name) > 32 {
+		return "name must be 1-32 characters long"
+	}
+	if cmd.Type == discordgo.ChatApplicationCommand {
+		if cmd.Description == "" || len(cmd.Description) > 100 {
+			return "description must be 1-100 characters long"
+		}
+	} else if cmd.Description != "" {
+		return "description must be empty for context menu commands"
+	}
+	return ""
+}
+
+func (srv *CommandsServer) find(key, id string) (int, *discordgo.ApplicationCommand) {
+	for i, cmd := range srv.commands[key] {
+		if cmd.ID == id {
+			return i, cmd
+		}
+	}
+	return -1, nil
+}
+
+func (srv *CommandsServer) serveCommands(w http.ResponseWriter, method, application, guild string, body []byte) {
+	key := scopeKey(application, guild)
+	switch method {
+	case http.MethodGet:
+		commands := srv.commands[key]
+		if commands == nil {
+			commands = []*discordgo.ApplicationCommand{}
+		}
+		writeJSON(w, http.StatusOK, commands)
+	case http.MethodPost:
+		var cmd discordgo.ApplicationCommand
+		if err := json.Unmarshal(body, &cmd); err != nil {
+			writeError(w, http.StatusBadRequest, 50109, "The request body contains invalid JSON.")
+			return
+		}
+		if msg := validateCommand(&cmd); msg != "" {
+			writeError(w, http.StatusBadRequest, 50035, "Invalid Form Body: "+msg)
+			return
+		}
+		// NOTE: creating a command with an existing name overwrites it.
+		for i, existing := range srv.commands[key] {
+			if existing.Name == cmd.Name && existing.Type == cmd.Type {
+				cmd.ID = existing.ID
+				srv.fill(&cmd, application, guild)
+				srv.commands[key][i] = &cmd
+				writeJSON(w, http.StatusOK, &cmd)
+				return
+			}
+		}
+		if len(srv.commands[key]) >= maxCommands {
+			writeError(w, http.StatusBadRequest, 30032, "Maximum number of application commands reached")
+			return
+		}
+		srv.fill(&cmd, application, guild)
+		srv.commands[key] = append(srv.commands[key], &cmd)
+		writeJSON(w, http.StatusCreated, &cmd)
+	case http.MethodPut:
+		var commands []*discordgo.ApplicationCommand
+		if err := json.Unmarshal(body, &commands); err != nil {
+			writeError(w, http.StatusBadRequest, 50109, "The request body contains invalid JSON.")
+			return
+		}
+		if len(commands) > maxCommands {
+			writeError(w, http.StatusBadRequest, 30032, "Maximum number of application commands reached")
+			return
+		}
+		for _, cmd := range commands {
+			if msg := validateCommand(cmd); msg != "" {
+				writeError(w, http.StatusBadRequest, 50035, "Invalid Form Body: "+msg)
+				return
+			}
+			cmd.ID = ""
+			for _, existing := range srv.commands[key] {
+				if existing.Name == cmd.Name && existing.Type == cmd.Type {
+					cmd.ID = existing.ID
+				}
+			}
+			srv.fill(cmd, application, guild)
+		}
+		if commands == nil {
+			commands = []*discordgo.ApplicationCommand{}
+		}
+		srv.commands[key] = commands
+		writeJSON(w, http.StatusOK, commands)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, 0, "405: Method Not Allowed")
+	}
+}
+
+func (srv *CommandsServer) serveCommand(w http.ResponseWriter, method, application, guild, id string, body []byte) {
+	key := scopeKey(application, guild)
+	i, cmd := srv.find(key, id)
+	if cmd == nil {
+		writeError(w, http.StatusNotFound, 10063, "Unknown application command")
+		return
+	}
+
+	switch method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, cmd)
+	case http.MethodPatch:
+		edited := *cmd
+		if err := json.Unmarshal(body, &edited); err != nil {
+			writeError(w, http.StatusBadRequest, 50109, "The request body contains invalid JSON.")
+			return
+		}
+		if msg := validateCommand(&edited); msg != "" {
+			writeError(w, http.StatusBadRequest, 50035, "Invalid Form Body: "+msg)
+			return
+		}
+		edited.ID = cmd.ID
+		srv.fill(&edited, application, guild)
+		srv.commands[key][i] = &edited
+		writeJSON(w, http.StatusOK, &edited)
+	case http.MethodDelete:
+		srv.commands[key] = append(srv.commands[key][:i], srv.commands[key][i+1:]...)
+		delete(srv.permissions, key+"/"+id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, 0, "405: Method Not Allowed")
+	}
+}
+
+func (srv *CommandsServer) serveGuildPermissions(w http.ResponseWriter, method, application, guild string, body []byte) {
+	key := scopeKey(application, guild)
+	switch method {
+	case http.MethodGet:
+		permissions := []*discordgo.GuildApplicationCommandPermissions{}
+		for _, cmd := range append(srv.commands[scopeKey(application, "")], srv.commands[key]...) {
+			if p, ok := srv.permissions[key+"/"+cmd.ID]; ok {
+				permissions = append(permissions, p)
+			}
+		}
+		writeJSON(w, http.StatusOK, permissions)
+	case http.MethodPut:
+		var permissions []*discordgo.GuildApplicationCommandPermissions
+		if err := json.Unmarshal(body, &permissions); err != nil {
+			writeError(w, http.StatusBadRequest, 50109, "The request body contains invalid JSON.")
+			return
+		}
+		for _, p := range permissions {
+			p.ApplicationID, p.GuildID = application, guild
+			srv.permissions[key+"/"+p.ID] = p
+		}
+		writeJSON(w, http.StatusOK, permissions)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, 0, "405: Method Not Allowed")
+	}
+}
+
+func (srv *CommandsServer) serveCommandPermissions(w http.ResponseWriter, method, application, guild, id string, body []byte) {
+	key := scopeKey(application, guild)
+	if _, cmd := srv.find(key, id); cmd == nil {
+		if _, cmd = srv.find(scopeKey(application, ""), id); cmd == nil {
+			writeError(w, http.StatusNotFound, 10063, "Unknown application command")
+			return
+		}
+	}
+
+	switch method {
+	case http.MethodGet:
+		p, ok := srv.permissions[key+"/"+id]
+		if !ok {
+			writeError(w, http.StatusNotFound, 10066, "Unknown application command permissions")
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+	case http.MethodPut:
+		var list discordgo.ApplicationCommandPermissionsList
+		if err := json.Unmarshal(body, &list); err != nil {
+			writeError(w, http.StatusBadRequest, 50109, "The request body contains invalid JSON.")
+			return
+		}
+		p := &discordgo.GuildApplicationCommandPermissions{
+			ID:            id,
+			ApplicationID: application,
+			GuildID:       guild,
+			Permissions:   list.Permissions,
+		}
+		srv.permissions[key+"/"+id] = p
+		writeJSON(w, http.StatusOK, p)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, 0, "405: Method Not Allowed")
+	}
+}
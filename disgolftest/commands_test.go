@@ -0,0 +1,85 @@
+package disgolftest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/FedorLap2006/disgolf/disgolftest"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandsServer_Sync(t *testing.T) {
+	srv := disgolftest.NewCommandsServer()
+	s := srv.Session()
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{Name: "ping", Description: "Ping"},
+		{Name: "pong", Description: "Pong"},
+	})
+
+	srv.RateLimit(http.MethodPut, "applications/*/guilds/*/commands", 10*time.Millisecond, 1)
+	if !assert.NoError(t, r.Sync(s, "app", "guild")) {
+		return
+	}
+	assert.Len(t, srv.Requests(), 2)
+	assert.Len(t, srv.Commands("app", "guild"), 2)
+	assert.Empty(t, srv.Commands("app", ""))
+
+	var ping *discordgo.ApplicationCommand
+	for _, cmd := range srv.Commands("app", "guild") {
+		if cmd.Name == "ping" {
+			ping = cmd
+		}
+	}
+	r.Unregister("pong")
+	assert.NoError(t, r.Sync(s, "app", "guild"))
+	if commands := srv.Commands("app", "guild"); assert.Len(t, commands, 1) {
+		assert.Equal(t, ping.ID, commands[0].ID)
+	}
+
+	srv.Fail(disgolftest.Failure{Method: http.MethodPut, Status: http.StatusBadRequest, Code: 50035, Message: "Invalid Form Body", Times: 1})
+	err := r.Sync(s, "app", "")
+	if assert.IsType(t, &discordgo.RESTError{}, err) {
+		assert.Equal(t, 50035, err.(*discordgo.RESTError).Message.Code)
+	}
+	assert.NoError(t, r.Sync(s, "app", ""))
+	assert.Len(t, srv.Commands("app", ""), 1)
+}
+
+func TestCommandsServer_Endpoints(t *testing.T) {
+	srv := disgolftest.NewCommandsServer()
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	defer disgolftest.UseEndpoints(server.URL)()
+
+	s, _ := discordgo.New("Bot test")
+	cmd, err := s.ApplicationCommandCreate("app", "", &discordgo.ApplicationCommand{Name: "ping", Description: "Ping"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cmd, err = s.ApplicationCommandEdit("app", "", cmd.ID, &discordgo.ApplicationCommand{Name: "ping", Description: "Edited"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Edited", cmd.Description)
+
+	err = s.ApplicationCommandPermissionsEdit("app", "guild", cmd.ID, &discordgo.ApplicationCommandPermissionsList{
+		Permissions: []*discordgo.ApplicationCommandPermissions{{ID: "role", Type: discordgo.ApplicationCommandPermissionTypeRole, Permission: true}},
+	})
+	assert.NoError(t, err)
+	permissions, err := s.ApplicationCommandPermissions("app", "guild", cmd.ID)
+	if assert.NoError(t, err) {
+		assert.Len(t, permissions.Permissions, 1)
+	}
+
+	commands, err := s.ApplicationCommands("app", "")
+	assert.NoError(t, err)
+	assert.Len(t, commands, 1)
+
+	assert.NoError(t, s.ApplicationCommandDelete("app", "", cmd.ID))
+	assert.Empty(t, srv.Commands("app", ""))
+	_, err = s.ApplicationCommand("app", "", cmd.ID)
+	assert.Error(t, err)
+}
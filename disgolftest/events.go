@@ -0,0 +1,194 @@
+package disgolftest
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// Option is a command option of a synthetic interaction, along with the data it resolves to.
+type Option struct {
+	Data     *discordgo.ApplicationCommandInteractionDataOption
+	Resolved *discordgo.ApplicationCommandInteractionDataResolved
+}
+
+func valueOption(name string, typ discordgo.ApplicationCommandOptionType, value interface{}) *Option {
+	return &Option{Data: &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: typ, Value: value}}
+}
+
+// String makes a string option.
+func String(name, value string) *Option {
+	return valueOption(name, discordgo.ApplicationCommandOptionString, value)
+}
+
+// Integer makes an integer option.
+func Integer(name string, value int64) *Option {
+	// NOTE: numbers are decoded from JSON as float64, and discordgo relies on that.
+	return valueOption(name, discordgo.ApplicationCommandOptionInteger, float64(value))
+}
+
+// Number makes a number option.
+func Number(name string, value float64) *Option {
+	return valueOption(name, discordgo.ApplicationCommandOptionNumber, value)
+}
+
+// Boolean makes a boolean option.
+func Boolean(name string, value bool) *Option {
+	return valueOption(name, discordgo.ApplicationCommandOptionBoolean, value)
+}
+
+// User makes a user option resolving to the user.
+func User(name string, user *discordgo.User) *Option {
+	opt := valueOption(name, discordgo.ApplicationCommandOptionUser, user.ID)
+	opt.Resolved = &discordgo.ApplicationCommandInteractionDataResolved{
+		Users: map[string]*discordgo.User{user.ID: user},
+	}
+	return opt
+}
+
+// Member makes a user option resolving to the guild member.
+func Member(name string, member *discordgo.Member) *Option {
+	opt := User(name, member.User)
+	partial := *member
+	partial.User = nil
+	opt.Resolved.Members = map[string]*discordgo.Member{member.User.ID: &partial}
+	return opt
+}
+
+// Channel makes a channel option resolving to the channel.
+func Channel(name string, channel *discordgo.Channel) *Option {
+	opt := valueOption(name, discordgo.ApplicationCommandOptionChannel, channel.ID)
+	opt.Resolved = &discordgo.ApplicationCommandInteractionDataResolved{
+		Channels: map[string]*discordgo.Channel{channel.ID: channel},
+	}
+	return opt
+}
+
+// Role makes a role option resolving to the role.
+func Role(name string, role *discordgo.Role) *Option {
+	opt := valueOption(name, discordgo.ApplicationCommandOptionRole, role.ID)
+	opt.Resolved = &discordgo.ApplicationCommandInteractionDataResolved{
+		Roles: map[string]*discordgo.Role{role.ID: role},
+	}
+	return opt
+}
+
+// Attachment makes an attachment option resolving to the attachment.
+func Attachment(name string, attachment *discordgo.MessageAttachment) *Option {
+	opt := valueOption(name, discordgo.ApplicationCommandOptionAttachment, attachment.ID)
+	opt.Resolved = &discordgo.ApplicationCommandInteractionDataResolved{
+		Attachments: map[string]*discordgo.MessageAttachment{attachment.ID: attachment},
+	}
+	return opt
+}
+
+// Focused marks the option as focused, for autocomplete interactions.
+func Focused(opt *Option) *Option {
+	opt.Data.Focused = true
+	return opt
+}
+
+func nestedOption(name string, typ discordgo.ApplicationCommandOptionType, options []*Option) *Option {
+	opt := &Option{Data: &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: typ}}
+	for _, o := range options {
+		opt.Data.Options = append(opt.Data.Options, o.Data)
+		opt.Resolved = mergeResolved(opt.Resolved, o.Resolved)
+	}
+	return opt
+}
+
+// SubCommand makes a subcommand option.
+func SubCommand(name string, options ...*Option) *Option {
+	return nestedOption(name, discordgo.ApplicationCommandOptionSubCommand, options)
+}
+
+// SubCommandGroup makes a subcommand group option.
+func SubCommandGroup(name string, subcommands ...*Option) *Option {
+	return nestedOption(name, discordgo.ApplicationCommandOptionSubCommandGroup, subcommands)
+}
+
+func mergeResolved(dst, src *discordgo.ApplicationCommandInteractionDataResolved) *discordgo.ApplicationCommandInteractionDataResolved {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = &discordgo.ApplicationCommandInteractionDataResolved{}
+	}
+	for id, v := range src.Users {
+		if dst.Users == nil {
+			dst.Users = make(map[string]*discordgo.User)
+		}
+		dst.Users[id] = v
+	}
+	for id, v := range src.Members {
+		if dst.Members == nil {
+			dst.Members = make(map[string]*discordgo.Member)
+		}
+		dst.Members[id] = v
+	}
+	for id, v := range src.Roles {
+		if dst.Roles == nil {
+			dst.Roles = make(map[string]*discordgo.Role)
+		}
+		dst.Roles[id] = v
+	}
+	for id, v := range src.Channels {
+		if dst.Channels == nil {
+			dst.Channels = make(map[string]*discordgo.Channel)
+		}
+		dst.Channels[id] = v
+	}
+	for id, v := range src.Messages {
+		if dst.Messages == nil {
+			dst.Messages = make(map[string]*discordgo.Message)
+		}
+		dst.Messages[id] = v
+	}
+	for id, v := range src.Attachments {
+		if dst.Attachments == nil {
+			dst.Attachments = make(map[string]*discordgo.MessageAttachment)
+		}
+		dst.Attachments[id] = v
+	}
+	return dst
+}
+
+func commandData(name string, options []*Option) discordgo.ApplicationCommandInteractionData {
+	opt := nestedOption(name, 0, options)
+	return discordgo.ApplicationCommandInteractionData{
+		Name:     name,
+		Options:  opt.Data.Options,
+		Resolved: opt.Resolved,
+	}
+}
+
+// CommandInteraction makes a chat input command interaction.
+// The source of the interaction (guild, channel, user) is filled by Harness, if it is empty.
+func CommandInteraction(name string, options ...*Option) *discordgo.Interaction {
+	return &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommand,
+		Data: commandData(name, options),
+	}
+}
+
+// AutocompleteInteraction makes an autocomplete interaction. Use Focused to mark the option being completed.
+func AutocompleteInteraction(name string, options ...*Option) *discordgo.Interaction {
+	return &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommandAutocomplete,
+		Data: commandData(name, options),
+	}
+}
+
+// ComponentInteraction makes a message component interaction. Values are set for select menus.
+func ComponentInteraction(customID string, values ...string) *discordgo.Interaction {
+	typ := discordgo.ButtonComponent
+	if values != nil {
+		typ = discordgo.SelectMenuComponent
+	}
+	return &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{
+			CustomID:      customID,
+			ComponentType: typ,
+			Values:        values,
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package disgolftest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/stretchr/testify/assert"
+)
+
+// UpdateGoldenEnv is the environment variable, which makes Golden overwrite the golden files instead of comparing against them.
+const UpdateGoldenEnv = "DISGOLF_UPDATE_GOLDEN"
+
+// updateGolden reports whether the golden files should be updated. Besides the environment variable,
+// the -update flag is honoured, if the test binary defines it.
+// NOTE: the package does not register the flag itself, since it would conflict with the flags of the importing packages.
+func updateGolden() bool {
+	if v, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnv)); v {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		v, _ := strconv.ParseBool(f.Value.String())
+		return v
+	}
+	return false
+}
+
+// Golden compares data against the golden file at path.
+// If DISGOLF_UPDATE_GOLDEN environment variable is set (or tests are run with -update flag, if one is defined), the file is overwritten instead.
+func Golden(t testing.TB, path string, data []byte) {
+	t.Helper()
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run tests with %s=1 to create it)", err, UpdateGoldenEnv)
+	}
+	assert.Equal(t, string(expected), string(data), "%s is outdated (run tests with %s=1 to update it)", path, UpdateGoldenEnv)
+}
+
+// GoldenSchema compares the schema of the router's commands (see disgolf.Router.ExportSchema) against the golden file at path.
+func GoldenSchema(t testing.TB, r *disgolf.Router, path string) {
+	t.Helper()
+	schema, err := r.ExportSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	Golden(t, path, append(schema, '\n'))
+}
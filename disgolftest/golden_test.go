@@ -0,0 +1,41 @@
+package disgolftest_test
+
+import (
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/FedorLap2006/disgolf/disgolftest"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestGoldenSchema(t *testing.T) {
+	dmPermission := false
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name:        "ping",
+			Description: "Check the latency",
+		},
+		{
+			Name:         "config",
+			Description:  "Manage the configuration",
+			DMPermission: &dmPermission,
+			SubCommands: disgolf.NewRouter([]*disgolf.Command{
+				{Name: "set", Description: "Set a value", Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "key", Description: "Key", Required: true},
+					{Type: discordgo.ApplicationCommandOptionString, Name: "value", Description: "Value", Required: true},
+				}},
+				{Name: "get", Description: "Get a value", Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "key", Description: "Key", Required: true},
+				}},
+				{Name: "reset", Description: "Reset all values"},
+			}),
+		},
+		{
+			Name: "Report",
+			Type: discordgo.MessageApplicationCommand,
+			NSFW: true,
+		},
+	})
+
+	disgolftest.GoldenSchema(t, r, "testdata/schema.golden.json")
+}
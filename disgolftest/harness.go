@@ -0,0 +1,137 @@
+package disgolftest
+
+import (
+	"net/http"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Harness dispatches synthetic events through a router and captures REST API calls made by the handlers.
+//
+// NOTE: calls are attributed to the event during which they were made,
+// so handlers responding asynchronously should be awaited through Transport.Calls.
+type Harness struct {
+	Router    *disgolf.Router
+	Session   *discordgo.Session
+	Transport *Transport
+	// MessageHandlerConfig is used to handle message events.
+	MessageHandlerConfig *disgolf.MessageHandlerConfig
+	// Handler of the message events. It is made from MessageHandlerConfig on the first message event.
+	messageHandler func(*discordgo.Session, *discordgo.MessageCreate)
+
+	// Defaults for the source of the events. Set GuildID to empty string to simulate direct messages.
+	ApplicationID string
+	GuildID       string
+	ChannelID     string
+	// User is the author of the events.
+	User *discordgo.User
+	// Member of the author in the guild. If nil, a member without roles is used.
+	Member *discordgo.Member
+	// Locale of the user.
+	Locale discordgo.Locale
+}
+
+// New constructs a harness for the router.
+// The session of the harness uses a fake transport, and its state has the bot user set.
+func New(r *disgolf.Router) *Harness {
+	transport := &Transport{}
+	session, _ := discordgo.New("Bot test")
+	session.Client = &http.Client{Transport: transport}
+	session.State.User = &discordgo.User{ID: "1", Username: "bot", Bot: true}
+
+	return &Harness{
+		Router:               r,
+		Session:              session,
+		Transport:            transport,
+		MessageHandlerConfig: &disgolf.MessageHandlerConfig{Prefixes: []string{"!"}},
+		ApplicationID:        "1",
+		GuildID:              "2",
+		ChannelID:            "3",
+		User:                 &discordgo.User{ID: "4", Username: "user"},
+		Locale:               discordgo.EnglishUS,
+	}
+}
+
+// Interaction fills the empty source fields of the interaction with the harness defaults.
+func (h *Harness) Interaction(i *discordgo.Interaction) *discordgo.Interaction {
+	if i.ID == "" {
+		i.ID = h.Transport.NextID()
+	}
+	if i.Token == "" {
+		i.Token = "token-" + i.ID
+	}
+	if i.AppID == "" {
+		i.AppID = h.ApplicationID
+	}
+	if i.ChannelID == "" {
+		i.ChannelID = h.ChannelID
+	}
+	if i.Locale == "" {
+		i.Locale = h.Locale
+	}
+	if i.GuildID == "" && i.User == nil && i.Member == nil {
+		i.GuildID = h.GuildID
+	}
+	if i.Member == nil && i.User == nil {
+		if i.GuildID == "" {
+			i.User = h.User
+		} else {
+			member := h.Member
+			if member == nil {
+				member = &discordgo.Member{GuildID: i.GuildID}
+			}
+			member.User = h.User
+			i.Member = member
+		}
+	}
+	return i
+}
+
+// Dispatch routes the interaction, and returns the calls made during it.
+func (h *Harness) Dispatch(i *discordgo.Interaction) Calls {
+	start := h.Transport.count()
+	h.Router.HandleInteraction(h.Session, &discordgo.InteractionCreate{Interaction: h.Interaction(i)})
+	return h.Transport.since(start)
+}
+
+// Command dispatches a chat input command interaction.
+func (h *Harness) Command(name string, options ...*Option) Calls {
+	return h.Dispatch(CommandInteraction(name, options...))
+}
+
+// Message fills the empty source fields of the message with the harness defaults.
+func (h *Harness) Message(m *discordgo.Message) *discordgo.Message {
+	if m.ID == "" {
+		m.ID = h.Transport.NextID()
+	}
+	if m.ChannelID == "" {
+		m.ChannelID = h.ChannelID
+	}
+	if m.Author == nil {
+		m.Author = h.User
+	}
+	if m.GuildID == "" && m.Member == nil && h.GuildID != "" {
+		m.GuildID = h.GuildID
+		m.Member = h.Member
+		if m.Member == nil {
+			m.Member = &discordgo.Member{}
+		}
+	}
+	return m
+}
+
+// DispatchMessage handles the message with the handler made by Router.MakeMessageHandler, and returns the calls made during it.
+func (h *Harness) DispatchMessage(m *discordgo.Message) Calls {
+	if h.messageHandler == nil {
+		h.messageHandler = h.Router.MakeMessageHandler(h.MessageHandlerConfig)
+	}
+	start := h.Transport.count()
+	h.messageHandler(h.Session, &discordgo.MessageCreate{Message: h.Message(m)})
+	return h.Transport.since(start)
+}
+
+// Send dispatches a message with the content.
+func (h *Harness) Send(content string) Calls {
+	return h.DispatchMessage(&discordgo.Message{Content: content})
+}
@@ -0,0 +1,66 @@
+package disgolftest_test
+
+import (
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/FedorLap2006/disgolf/disgolftest"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHarness(t *testing.T) {
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name: "profile",
+		SubCommands: disgolf.NewRouter([]*disgolf.Command{{
+			Name: "view",
+			SubCommands: disgolf.NewRouter([]*disgolf.Command{{
+				Name: "user",
+				Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+					user := ctx.Interaction.ApplicationCommandData().Resolved.Users[ctx.Options["target"].Value.(string)]
+					_ = ctx.ReplyText("profile of " + user.Username)
+					_ = ctx.ReplyText("more")
+				}),
+				MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+					_, _ = ctx.Reply("profile of "+ctx.Arguments[0], false)
+				}),
+			}}),
+		}}),
+	}})
+	h := disgolftest.New(r)
+
+	calls := h.Command("profile", disgolftest.SubCommandGroup("view",
+		disgolftest.SubCommand("user", disgolftest.User("target", &discordgo.User{ID: "42", Username: "fedor"})),
+	))
+	if responses := calls.InteractionResponses(); assert.Len(t, responses, 1) {
+		assert.Equal(t, "profile of fedor", responses[0].Data.Content)
+	}
+	if followups := calls.Followups(); assert.Len(t, followups, 1) {
+		assert.Equal(t, "more", followups[0].Content)
+	}
+
+	calls = h.Send("!profile view user fedor")
+	if messages := calls.Messages(); assert.Len(t, messages, 1) {
+		assert.Equal(t, "profile of fedor", messages[0].Content)
+		assert.NotNil(t, messages[0].Reference)
+	}
+}
+
+func TestHarness_Reset(t *testing.T) {
+	var h *disgolftest.Harness
+	h = disgolftest.New(disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name:    "ping",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { _ = ctx.ReplyText("pong") }),
+		},
+		{
+			Name:    "reset",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { h.Transport.Reset() }),
+		},
+	}))
+	h.Command("ping")
+
+	var calls disgolftest.Calls
+	assert.NotPanics(t, func() { calls = h.Command("reset") })
+	assert.Empty(t, calls)
+}
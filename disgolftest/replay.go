@@ -0,0 +1,48 @@
+package disgolftest
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/FedorLap2006/disgolf"
+)
+
+// ReadRecording reads records written by disgolf.Recorder.
+func ReadRecording(r io.Reader) (records []*disgolf.Record, err error) {
+	dec := json.NewDecoder(r)
+	for {
+		var record disgolf.Record
+		if err = dec.Decode(&record); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+}
+
+// LoadRecording reads records from the file written by disgolf.Recorder.
+func LoadRecording(path string) ([]*disgolf.Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadRecording(f)
+}
+
+// Replay dispatches the recorded events in order, and returns the calls made during each of them.
+// Fields removed by redaction (e.g. interaction tokens) are filled with the harness defaults.
+func (h *Harness) Replay(records []*disgolf.Record) []Calls {
+	calls := make([]Calls, len(records))
+	for i, record := range records {
+		switch {
+		case record.Interaction != nil:
+			calls[i] = h.Dispatch(record.Interaction)
+		case record.Message != nil:
+			calls[i] = h.DispatchMessage(record.Message)
+		}
+	}
+	return calls
+}
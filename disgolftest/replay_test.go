@@ -0,0 +1,50 @@
+package disgolftest_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/FedorLap2006/disgolf/disgolftest"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder_Replay(t *testing.T) {
+	var recording bytes.Buffer
+	recorder := disgolf.NewRecorder(&recording)
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name:              "greet",
+		CommonMiddlewares: []disgolf.Middleware{recorder},
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			_ = ctx.ReplyText("hello, " + ctx.Author().Username + " " + ctx.Options["name"].StringValue())
+		}),
+		MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+			_, _ = ctx.Reply("hello, "+ctx.Message.Author.Username+" "+ctx.Arguments[0], false)
+		}),
+	}})
+
+	h := disgolftest.New(r)
+	h.User = &discordgo.User{ID: "42", Username: "fedor", Email: "fedor@example.com"}
+	h.Command("greet", disgolftest.String("name", "world"))
+	h.Send("!greet there")
+	assert.NoError(t, recorder.Err())
+
+	assert.NotContains(t, recording.String(), "fedor")
+	assert.NotContains(t, recording.String(), "token-")
+
+	records, err := disgolftest.ReadRecording(&recording)
+	if !assert.NoError(t, err) || !assert.Len(t, records, 2) {
+		return
+	}
+	assert.NotNil(t, records[0].Interaction)
+	assert.NotNil(t, records[1].Message)
+
+	calls := disgolftest.New(r).Replay(records)
+	if responses := calls[0].InteractionResponses(); assert.Len(t, responses, 1) {
+		assert.Equal(t, "hello, user42 world", responses[0].Data.Content)
+	}
+	if messages := calls[1].Messages(); assert.Len(t, messages, 1) {
+		assert.Equal(t, "hello, user42 there", messages[0].Content)
+	}
+}
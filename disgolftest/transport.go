@@ -0,0 +1,258 @@
+// Package disgolftest provides utilities for testing disgolf bots without connecting to Discord.
+// It builds synthetic events, dispatches them through a router, and captures REST API calls made by the handlers.
+package disgolftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Call is a REST API call captured by Transport.
+type Call struct {
+	Method string
+	// Path of the endpoint relative to discordgo.EndpointAPI, e.g. "channels/123/messages".
+	Path string
+	// Body is the JSON payload of the request. For multipart requests it is the payload_json part.
+	Body []byte
+}
+
+// Decode decodes the body of the call into v.
+func (c Call) Decode(v interface{}) error {
+	return json.Unmarshal(c.Body, v)
+}
+
+// Calls is a list of captured REST API calls.
+type Calls []Call
+
+// Filter returns calls with the method, which path matches the pattern.
+// In the pattern, "*" matches a single path segment.
+func (calls Calls) Filter(method, pattern string) (filtered Calls) {
+	for _, call := range calls {
+		if call.Method == method && matchPath(pattern, call.Path) {
+			filtered = append(filtered, call)
+		}
+	}
+	return
+}
+
+func matchPath(pattern, path string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if segment != "*" && segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// InteractionResponses returns initial interaction responses.
+func (calls Calls) InteractionResponses() (responses []*discordgo.InteractionResponse) {
+	for _, call := range calls.Filter(http.MethodPost, "interactions/*/*/callback") {
+		var response discordgo.InteractionResponse
+		if call.Decode(&response) == nil {
+			responses = append(responses, &response)
+		}
+	}
+	return
+}
+
+// Followups returns follow-up messages sent to interactions.
+func (calls Calls) Followups() (followups []*discordgo.WebhookParams) {
+	for _, call := range calls.Filter(http.MethodPost, "webhooks/*/*") {
+		var params discordgo.WebhookParams
+		if call.Decode(&params) == nil {
+			followups = append(followups, &params)
+		}
+	}
+	return
+}
+
+// ResponseEdits returns edits of the original interaction responses.
+func (calls Calls) ResponseEdits() (edits []*discordgo.WebhookEdit) {
+	for _, call := range calls.Filter(http.MethodPatch, "webhooks/*/*/messages/@original") {
+		var edit discordgo.WebhookEdit
+		if call.Decode(&edit) == nil {
+			edits = append(edits, &edit)
+		}
+	}
+	return
+}
+
+// Messages returns messages sent to channels.
+func (calls Calls) Messages() (messages []*discordgo.MessageSend) {
+	for _, call := range calls.Filter(http.MethodPost, "channels/*/messages") {
+		var message discordgo.MessageSend
+		if call.Decode(&message) == nil {
+			messages = append(messages, &message)
+		}
+	}
+	return
+}
+
+// Transport is a fake REST API transport, which captures all the calls.
+// Calls that create or edit messages are answered with a message built from the request,
+// other calls are answered with an empty response.
+type Transport struct {
+	// Fallback handles requests, which are not answered by the transport itself. If nil, "204 No Content" is returned.
+	Fallback http.RoundTripper
+
+	mtx    sync.Mutex
+	calls  Calls
+	lastID uint64
+}
+
+var apiPath = func() string {
+	u, _ := url.Parse(discordgo.EndpointAPI)
+	return u.Path
+}()
+
+// RoundTrip implements http.RoundTripper interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	payload, err := jsonPayload(req.Header.Get("Content-Type"), body)
+	if err != nil {
+		return nil, err
+	}
+	call := Call{
+		Method: req.Method,
+		Path:   strings.TrimPrefix(req.URL.Path, apiPath),
+		Body:   payload,
+	}
+
+	t.mtx.Lock()
+	t.calls = append(t.calls, call)
+	t.mtx.Unlock()
+
+	if t.Fallback != nil {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return t.Fallback.RoundTrip(req)
+	}
+
+	switch {
+	case call.Method == http.MethodPost && matchPath("channels/*/messages", call.Path),
+		call.Method == http.MethodPost && matchPath("webhooks/*/*", call.Path),
+		call.Method == http.MethodPatch && matchPath("webhooks/*/*/messages/*", call.Path),
+		call.Method == http.MethodPatch && matchPath("channels/*/messages/*", call.Path):
+		return t.messageResponse(req, call)
+	}
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Status:     "204 No Content",
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+// NextID generates a unique snowflake-like id.
+func (t *Transport) NextID() string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.lastID++
+	return strconv.FormatUint(1000000000000000000+t.lastID, 10)
+}
+
+func (t *Transport) messageResponse(req *http.Request, call Call) (*http.Response, error) {
+	var message discordgo.Message
+	if len(call.Body) != 0 {
+		_ = json.Unmarshal(call.Body, &message)
+	}
+
+	segments := strings.Split(call.Path, "/")
+	switch {
+	case segments[0] == "channels":
+		message.ChannelID = segments[1]
+	}
+	if len(segments) == 4 && segments[3] != "@original" {
+		message.ID = segments[3]
+	} else if len(segments) == 5 && segments[4] != "@original" {
+		message.ID = segments[4]
+	} else {
+		message.ID = t.NextID()
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(data)),
+		Request:    req,
+	}, nil
+}
+
+// Calls returns all captured calls.
+func (t *Transport) Calls() Calls {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return append(Calls(nil), t.calls...)
+}
+
+// Reset removes all captured calls.
+func (t *Transport) Reset() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.calls = nil
+}
+
+// since returns the calls made after the first n ones. n is clamped, since the calls may have been reset in the meantime.
+func (t *Transport) since(n int) Calls {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if n > len(t.calls) {
+		n = len(t.calls)
+	}
+	return append(Calls(nil), t.calls[n:]...)
+}
+
+func (t *Transport) count() int {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return len(t.calls)
+}
+
+// jsonPayload extracts the JSON payload from the request body.
+func jsonPayload(contentType string, body []byte) ([]byte, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return body, nil
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, fmt.Errorf("cannot find payload_json in multipart body: %w", err)
+		}
+		if part.FormName() == "payload_json" {
+			return ioutil.ReadAll(part)
+		}
+	}
+}
@@ -5,4 +5,51 @@ import "errors"
 var (
 	// ErrCommandNotExists means that the requested command does not exist.
 	ErrCommandNotExists = errors.New("command not exists")
+
+	// ErrContextMenuOptions means that a user or message command has options or subcommands, which Discord does not allow.
+	ErrContextMenuOptions = errors.New("context menu commands cannot have options or subcommands")
+
+	// ErrGuildOnly means that the command can only be used in guilds.
+	ErrGuildOnly = errors.New("this command can only be used in a server")
+	// ErrDMOnly means that the command can only be used in direct messages.
+	ErrDMOnly = errors.New("this command can only be used in direct messages")
+	// ErrNSFWOnly means that the command can only be used in NSFW channels.
+	ErrNSFWOnly = errors.New("this command can only be used in NSFW channels")
+	// ErrAlreadyResponded means that the initial response to the interaction was already sent.
+	ErrAlreadyResponded = errors.New("interaction has already been responded to")
+	// ErrInteractionFailed means that the interaction received through the HTTP endpoint has not been handled,
+	// because no handler has matched it, or the handler has panicked.
+	ErrInteractionFailed = errors.New("this interaction could not be handled")
+	// ErrFilesInHTTPResponse means that the initial response sent through the HTTP endpoint contains files, which is not supported.
+	ErrFilesInHTTPResponse = errors.New("files are not supported in initial responses to HTTP interactions")
+
+	// ErrUnknownApplication means that the application id was not specified and cannot be determined,
+	// because the session is not ready yet.
+	ErrUnknownApplication = errors.New("cannot determine application id: session is not ready")
+	// ErrShutdownTimeout means that the bot has not finished handling all the invocations in time while shutting down.
+	ErrShutdownTimeout = errors.New("timed out waiting for handlers to finish")
+
+	// ErrQueueFull means that the executor queue is full, and the invocation was rejected.
+	ErrQueueFull = errors.New("executor queue is full")
+	// ErrGuildQueueFull means that the guild has too many queued invocations, and the invocation was rejected.
+	ErrGuildQueueFull = errors.New("executor queue of the guild is full")
+	// ErrExecutorClosed means that the executor is closed, and the invocation was rejected.
+	ErrExecutorClosed = errors.New("executor is closed")
+
+	// ErrCollectorStopped means that the collector was stopped, or has collected the maximal amount of interactions.
+	ErrCollectorStopped = errors.New("collector is stopped")
+	// ErrCollectorCancelled means that the user has sent the cancellation keyword of the message collector.
+	ErrCollectorCancelled = errors.New("collector is cancelled")
+	// ErrCollectorTimeout means that the collector has not received interactions in time.
+	ErrCollectorTimeout = errors.New("collector timed out")
+	// ErrCollectorUnavailable means that the context was not created by a router, so there is nothing to collect from.
+	ErrCollectorUnavailable = errors.New("collectors are only available in contexts created by a router")
+
+	// ErrNoPages means that the page source of the paginator is empty.
+	ErrNoPages = errors.New("paginator has no pages")
+	// ErrAuthorOnly means that the interactive message can only be used by the author of the invocation.
+	ErrAuthorOnly = errors.New("only the author of the command can use this")
+
+	// ErrOwnerOnly means that the command can only be used by the owners of the bot.
+	ErrOwnerOnly = errors.New("this command can only be used by the bot owners")
 )
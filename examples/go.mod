@@ -6,6 +6,6 @@ replace github.com/FedorLap2006/disgolf => ../
 
 require (
 	github.com/FedorLap2006/disgolf v0.0.0-20211002235931-49e429efda50
-	github.com/bwmarrin/discordgo v0.23.3-0.20210821175000-0fad116c6c2a
+	github.com/bwmarrin/discordgo v0.26.1
 	github.com/joho/godotenv v1.4.0
 )
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -37,23 +38,25 @@ func main() {
 		log.Fatal(fmt.Errorf("failed to initialise session: %w", err))
 	}
 	loadModules(bot)
-	bot.AddHandler(bot.Router.HandleInteraction)
-	bot.AddHandler(bot.Router.MakeMessageHandler(&disgolf.MessageHandlerConfig{
+	bot.AddHandler(func(*discordgo.Session, *discordgo.Ready) { log.Println("Ready!") })
+	bot.MessageHandlerConfig = &disgolf.MessageHandlerConfig{
 		Prefixes:      []string{"d.", "dis.", "disgolf."},
 		MentionPrefix: true,
-	}))
-	bot.AddHandler(func(*discordgo.Session, *discordgo.Ready) { log.Println("Ready!") })
-	err = bot.Open()
-	if err != nil {
-		log.Fatal(fmt.Errorf("failed to open session: %w", err))
 	}
-	err = bot.Router.Sync(bot.Session, "", os.Getenv("TEST_GUILD_ID"))
-	if err != nil {
-		log.Fatal(fmt.Errorf("failed to sync commands: %w", err))
+	bot.SyncOnReady = true
+	if guild := os.Getenv("TEST_GUILD_ID"); guild != "" {
+		bot.SyncGuilds = []string{guild}
 	}
 
-	ech := make(chan os.Signal)
-	signal.Notify(ech, os.Kill, syscall.SIGTERM) //nolint:staticcheck
-	<-ech
-	_ = bot.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	stchan := make(chan os.Signal, 1)
+	signal.Notify(stchan, syscall.SIGTERM, os.Interrupt)
+	go func() {
+		<-stchan
+		cancel()
+	}()
+
+	if err = bot.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
 }
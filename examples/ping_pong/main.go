@@ -1,12 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/FedorLap2006/disgolf"
 	"github.com/bwmarrin/discordgo"
@@ -58,30 +58,22 @@ func main() {
 	bot.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
 		log.Println("Bot is up!")
 	})
-	bot.AddHandler(bot.Router.HandleInteraction)
-	bot.AddHandler(bot.Router.MakeMessageHandler(&disgolf.MessageHandlerConfig{
+	bot.MessageHandlerConfig = &disgolf.MessageHandlerConfig{
 		Prefixes:      []string{"d.", "dis.", "disgolf."},
 		MentionPrefix: true,
-	}))
-
-	err = bot.Open()
-	if err != nil {
-		log.Fatal(fmt.Errorf("open exited with a error: %w", err))
-	}
-	defer bot.Close()
-	err = bot.Router.Sync(bot.Session, "", "679281186975252480")
-	if err != nil {
-		log.Fatal(fmt.Errorf("cannot publish commands: %w", err))
 	}
+	bot.SyncOnReady = true
+	bot.SyncGuilds = []string{"679281186975252480"}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	stchan := make(chan os.Signal, 1)
-	signal.Notify(stchan, syscall.SIGTERM, os.Interrupt, syscall.SIGSEGV)
-end:
-	for {
-		select {
-		case <-stchan:
-			break end
-		default:
-		}
-		time.Sleep(time.Second)
+	signal.Notify(stchan, syscall.SIGTERM, os.Interrupt)
+	go func() {
+		<-stchan
+		cancel()
+	}()
+
+	if err = bot.Run(ctx); err != nil {
+		log.Fatal(err)
 	}
 }
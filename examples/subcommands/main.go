@@ -1,12 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/FedorLap2006/disgolf"
 	"github.com/bwmarrin/discordgo"
@@ -29,32 +29,20 @@ func main() {
 		Name:        "subcommands",
 		Description: "Lo and behold, subcommands are coming!",
 		Type:        discordgo.ChatApplicationCommand,
-		MessageMiddlewares: []disgolf.MessageHandler{
-			disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+		CommonMiddlewares: []disgolf.Middleware{
+			disgolf.MiddlewareFunc(func(inv disgolf.Invocation) {
 				fmt.Println("middleware")
-				ctx.Next()
-			}),
-		},
-		Middlewares: []disgolf.Handler{
-			disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
-				fmt.Println("middleware")
-				ctx.Next()
+				inv.Next()
 			}),
 		},
 		SubCommands: disgolf.NewRouter([]*disgolf.Command{
 			{
 				Name:        "group",
 				Description: "Subcommand group",
-				MessageMiddlewares: []disgolf.MessageHandler{
-					disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
-						fmt.Println("group middleware")
-						ctx.Next()
-					}),
-				},
-				Middlewares: []disgolf.Handler{
-					disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+				CommonMiddlewares: []disgolf.Middleware{
+					disgolf.MiddlewareFunc(func(inv disgolf.Invocation) {
 						fmt.Println("group middleware")
-						ctx.Next()
+						inv.Next()
 					}),
 				},
 				SubCommands: disgolf.NewRouter([]*disgolf.Command{
@@ -70,16 +58,10 @@ func main() {
 						MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
 							_, _ = ctx.Reply("hi (group)", false)
 						}),
-						MessageMiddlewares: []disgolf.MessageHandler{
-							disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
-								fmt.Println("individual middleware")
-								ctx.Next()
-							}),
-						},
-						Middlewares: []disgolf.Handler{
-							disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
-								fmt.Println("individual middleware")
-								ctx.Next()
+						CommonMiddlewares: []disgolf.Middleware{
+							disgolf.MiddlewareFunc(func(inv disgolf.Invocation) {
+								fmt.Printf("individual middleware: %v\n", inv.CommandPath())
+								inv.Next()
 							}),
 						},
 					},
@@ -100,16 +82,10 @@ func main() {
 				MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
 					_, _ = ctx.Reply("hi", false)
 				}),
-				MessageMiddlewares: []disgolf.MessageHandler{
-					disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
-						fmt.Println("individual middleware (2nd level)")
-						ctx.Next()
-					}),
-				},
-				Middlewares: []disgolf.Handler{
-					disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
-						fmt.Println("individual middleware (2nd level)")
-						ctx.Next()
+				CommonMiddlewares: []disgolf.Middleware{
+					disgolf.MiddlewareFunc(func(inv disgolf.Invocation) {
+						fmt.Printf("individual middleware (2nd level): %v\n", inv.CommandPath())
+						inv.Next()
 					}),
 				},
 			},
@@ -121,31 +97,22 @@ func main() {
 	bot.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
 		log.Println("Bot is up!")
 	})
-	bot.AddHandler(bot.Router.HandleInteraction)
-	bot.AddHandler(bot.Router.MakeMessageHandler(&disgolf.MessageHandlerConfig{
+	bot.MessageHandlerConfig = &disgolf.MessageHandlerConfig{
 		Prefixes:      []string{"d.", "dis.", "disgolf."},
 		MentionPrefix: true,
-	}))
-
-	err = bot.Open()
-	if err != nil {
-		log.Fatal(fmt.Errorf("open exited with a error: %w", err))
-	}
-	defer bot.Close()
-	err = bot.Router.Sync(bot.Session, "", "679281186975252480")
-	if err != nil {
-		log.Fatal(fmt.Errorf("cannot publish commands: %w", err))
 	}
+	bot.SyncOnReady = true
+	bot.SyncGuilds = []string{"679281186975252480"}
 
+	ctx, cancel := context.WithCancel(context.Background())
 	stchan := make(chan os.Signal, 1)
-	signal.Notify(stchan, syscall.SIGTERM, os.Interrupt, syscall.SIGSEGV)
-end:
-	for {
-		select {
-		case <-stchan:
-			break end
-		default:
-		}
-		time.Sleep(time.Second)
+	signal.Notify(stchan, syscall.SIGTERM, os.Interrupt)
+	go func() {
+		<-stchan
+		cancel()
+	}()
+
+	if err = bot.Run(ctx); err != nil {
+		log.Fatal(err)
 	}
 }
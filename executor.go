@@ -0,0 +1,192 @@
+package disgolf
+
+import (
+	"sync"
+)
+
+// An Executor runs invocation handlers. It allows limiting the amount of concurrently running handlers.
+//
+// Execute either schedules the task and returns nil, or rejects it with an error.
+// The router waits for scheduled tasks to finish, so the caller of the router (e.g. discordgo event handler)
+// is released when the invocation is handled or rejected.
+type Executor interface {
+	Execute(guild string, task func()) error
+}
+
+// execute runs the task through the executor of the router. Rejected tasks are logged and recorded in metrics.
+// It returns false, if the task was rejected.
+//
+// A panic of the task is recovered on the worker, so the executor keeps running, and is re-raised on the caller,
+// as if the task had run there (e.g. so InteractionServer can reply with ErrInteractionFailed).
+// The panic outcome itself is recorded by the task (see Router.observe).
+func (r *Router) execute(kind InvocationKind, command, guild string, fields []interface{}, task func()) bool {
+	if r.Executor == nil {
+		task()
+		return true
+	}
+
+	done := make(chan struct{})
+	var panicked bool
+	var value interface{}
+	err := r.Executor.Execute(guild, func() {
+		defer close(done)
+		defer func() {
+			if v := recover(); v != nil {
+				panicked, value = true, v
+			}
+		}()
+		task()
+	})
+	if err != nil {
+		r.logger().Warn("invocation rejected", append(fields, "command", command, "error", err)...)
+		r.metrics().ObserveRejection(kind, command)
+		return false
+	}
+	<-done
+	if panicked {
+		r.logger().Error("invocation panicked on the executor", append(fields, "command", command, "panic", value)...)
+		panic(value)
+	}
+	return true
+}
+
+// WorkerPool is an Executor with a fixed amount of workers and a bounded queue.
+// Queued tasks are scheduled round-robin between guilds, so a busy guild cannot starve others.
+// Direct messages share a single queue.
+type WorkerPool struct {
+	// PerGuildLimit is the maximal amount of queued tasks of a single guild. Zero means no limit.
+	PerGuildLimit int
+	// OnReject is called when a task is rejected.
+	OnReject func(guild string, err error)
+
+	mtx      sync.Mutex
+	cond     *sync.Cond
+	queues   map[string][]func()
+	ring     []string
+	queued   int
+	pending  int // Tasks accepted and not yet finished, both queued and running.
+	capacity int
+	closed   bool
+	workers  sync.WaitGroup
+}
+
+// NewWorkerPool constructs a worker pool and starts its workers.
+// Tasks are rejected, when all workers are busy and queueSize tasks are already waiting for a free worker.
+// The pool has at least one worker, and a negative queue size is treated as zero.
+func NewWorkerPool(workers, queueSize int) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	p := &WorkerPool{
+		queues:   make(map[string][]func()),
+		capacity: workers + queueSize,
+	}
+	p.cond = sync.NewCond(&p.mtx)
+	p.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Execute implements Executor interface.
+func (p *WorkerPool) Execute(guild string, task func()) error {
+	p.mtx.Lock()
+	err := p.enqueue(guild, task)
+	p.mtx.Unlock()
+
+	if err != nil && p.OnReject != nil {
+		p.OnReject(guild, err)
+	}
+	return err
+}
+
+func (p *WorkerPool) enqueue(guild string, task func()) error {
+	switch {
+	case p.closed:
+		return ErrExecutorClosed
+	case p.pending >= p.capacity:
+		return ErrQueueFull
+	case p.PerGuildLimit > 0 && len(p.queues[guild]) >= p.PerGuildLimit:
+		return ErrGuildQueueFull
+	}
+
+	if len(p.queues[guild]) == 0 {
+		p.ring = append(p.ring, guild)
+	}
+	p.queues[guild] = append(p.queues[guild], task)
+	p.queued++
+	p.pending++
+	p.cond.Signal()
+	return nil
+}
+
+// next takes the task of the next guild in the ring. It returns nil, if the pool is closed and the queue is empty.
+func (p *WorkerPool) next() func() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for len(p.ring) == 0 {
+		if p.closed {
+			return nil
+		}
+		p.cond.Wait()
+	}
+
+	guild := p.ring[0]
+	p.ring = p.ring[1:]
+	queue := p.queues[guild]
+	task := queue[0]
+	queue[0] = nil
+	if queue = queue[1:]; len(queue) != 0 {
+		p.queues[guild] = queue
+		p.ring = append(p.ring, guild)
+	} else {
+		delete(p.queues, guild)
+	}
+	p.queued--
+	return task
+}
+
+func (p *WorkerPool) work() {
+	defer p.workers.Done()
+	for task := p.next(); task != nil; task = p.next() {
+		p.run(task)
+	}
+}
+
+func (p *WorkerPool) run(task func()) {
+	defer func() {
+		p.mtx.Lock()
+		p.pending--
+		p.mtx.Unlock()
+	}()
+	task()
+}
+
+// Queued returns the amount of tasks waiting for a free worker.
+func (p *WorkerPool) Queued() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.queued
+}
+
+// Running returns the amount of tasks being run by the workers.
+func (p *WorkerPool) Running() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.pending - p.queued
+}
+
+// Close stops accepting new tasks, and waits for the queued ones to finish.
+func (p *WorkerPool) Close() {
+	p.mtx.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mtx.Unlock()
+
+	p.workers.Wait()
+}
@@ -0,0 +1,118 @@
+package disgolf_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockPool occupies the only worker of the pool until the returned function is called.
+// The release function waits for the worker to become free.
+func blockPool(t *testing.T, pool *disgolf.WorkerPool) (release func()) {
+	started, gate := make(chan struct{}), make(chan struct{})
+	assert.NoError(t, pool.Execute("", func() {
+		close(started)
+		<-gate
+	}))
+	<-started
+	return func() {
+		close(gate)
+		assert.Eventually(t, func() bool { return pool.Running() == 0 }, time.Second, time.Millisecond)
+	}
+}
+
+func TestWorkerPool_Fairness(t *testing.T) {
+	pool := disgolf.NewWorkerPool(1, 10)
+	release := blockPool(t, pool)
+
+	var mtx sync.Mutex
+	var order []string
+	task := func(name string) func() {
+		return func() {
+			mtx.Lock()
+			defer mtx.Unlock()
+			order = append(order, name)
+		}
+	}
+	for _, name := range []string{"a1", "a2", "a3"} {
+		assert.NoError(t, pool.Execute("a", task(name)))
+	}
+	assert.NoError(t, pool.Execute("b", task("b1")))
+	assert.Equal(t, 4, pool.Queued())
+
+	release()
+	pool.Close()
+	assert.Equal(t, []string{"a1", "b1", "a2", "a3"}, order)
+	assert.Equal(t, disgolf.ErrExecutorClosed, pool.Execute("a", func() {}))
+}
+
+func TestWorkerPool_Backpressure(t *testing.T) {
+	var rejected []string
+	pool := disgolf.NewWorkerPool(1, 2)
+	pool.PerGuildLimit = 1
+	pool.OnReject = func(guild string, err error) { rejected = append(rejected, guild+": "+err.Error()) }
+	release := blockPool(t, pool)
+	defer pool.Close()
+	defer release()
+
+	assert.NoError(t, pool.Execute("a", func() {}))
+	assert.Equal(t, disgolf.ErrGuildQueueFull, pool.Execute("a", func() {}))
+	assert.NoError(t, pool.Execute("b", func() {}))
+	assert.Equal(t, disgolf.ErrQueueFull, pool.Execute("c", func() {}))
+	assert.Equal(t, []string{
+		"a: " + disgolf.ErrGuildQueueFull.Error(),
+		"c: " + disgolf.ErrQueueFull.Error(),
+	}, rejected)
+}
+
+func TestWorkerPool_NoWorkers(t *testing.T) {
+	pool := disgolf.NewWorkerPool(0, -1)
+	defer pool.Close()
+
+	done := make(chan struct{})
+	assert.NoError(t, pool.Execute("", func() { close(done) }))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("task has not been executed")
+	}
+}
+
+func TestRouter_Executor(t *testing.T) {
+	pool := disgolf.NewWorkerPool(1, 0)
+	defer pool.Close()
+	metrics := disgolf.NewPrometheusMetrics()
+
+	var called int
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name:    "ping",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { called++ }),
+	}})
+	r.Executor = pool
+	r.Metrics = metrics
+	dispatch := func() {
+		r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "guild",
+			Data:    discordgo.ApplicationCommandInteractionData{Name: "ping"},
+		}})
+	}
+
+	release := blockPool(t, pool)
+	dispatch()
+	assert.Equal(t, 0, called)
+
+	release()
+	dispatch()
+	assert.Equal(t, 1, called)
+
+	var b strings.Builder
+	_, _ = metrics.WriteTo(&b)
+	assert.Contains(t, b.String(), `disgolf_invocation_rejections_total{kind="slash",command="ping"} 1`+"\n")
+	assert.Contains(t, b.String(), `disgolf_invocations_total{kind="slash",command="ping"} 1`+"\n")
+}
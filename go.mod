@@ -3,9 +3,11 @@ module github.com/FedorLap2006/disgolf
 go 1.15
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/bwmarrin/discordgo v0.26.1
-	github.com/gorilla/websocket v1.5.0 // indirect
+	github.com/gorilla/websocket v1.5.0
 	github.com/stretchr/testify v1.7.0
 	golang.org/x/crypto v0.0.0-20220926161630-eccd6366d1be // indirect
 	golang.org/x/sys v0.0.0-20220928140112-f11e5e49a4ec // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
@@ -0,0 +1,239 @@
+package disgolf
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultInteractionResponseTimeout is the default time InteractionServer waits for the initial response,
+// before acknowledging the interaction on its own. Discord requires the response to be sent within 3 seconds.
+const DefaultInteractionResponseTimeout = 2500 * time.Millisecond
+
+const maxInteractionBodySize = 1 << 20
+
+// InteractionServer is an http.Handler receiving interactions through Discord outgoing webhooks,
+// as an alternative to the gateway. Interactions are dispatched through the Router, as they would be by HandleInteraction.
+//
+// The initial response of the handler is returned in the HTTP response body. If the handler does not respond in time,
+// the interaction is deferred, and the response is sent later by editing the original one.
+// Follow-ups are sent through the REST API using the Session.
+// Interactions, which no handler has received (e.g. unknown commands), or which handler has panicked,
+// are replied to with an ephemeral ErrInteractionFailed notice right away.
+// Spans of the invocations (see Router.Tracer) are started under the context of the HTTP request, so they nest under its span.
+type InteractionServer struct {
+	Router *Router
+	// Session is used for the REST API calls. It does not need to be connected to the gateway.
+	Session *discordgo.Session
+	// PublicKey of the application, used to verify request signatures.
+	PublicKey ed25519.PublicKey
+	// ResponseTimeout is the time to wait for the initial response. Defaults to DefaultInteractionResponseTimeout.
+	ResponseTimeout time.Duration
+}
+
+// NewInteractionServer constructs an InteractionServer from the hex-encoded public key of the application.
+func NewInteractionServer(r *Router, s *discordgo.Session, publicKey string) (*InteractionServer, error) {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key: expected %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+
+	return &InteractionServer{
+		Router:    r,
+		Session:   s,
+		PublicKey: key,
+	}, nil
+}
+
+// ServeHTTP implements http.Handler interface.
+func (srv *InteractionServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	req.Body = http.MaxBytesReader(w, req.Body, maxInteractionBodySize)
+	if !discordgo.VerifyInteraction(req, srv.PublicKey) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var interaction discordgo.Interaction
+	if err = json.Unmarshal(body, &interaction); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if interaction.Type == discordgo.InteractionPing {
+		writeInteractionResponse(w, &discordgo.InteractionResponse{Type: discordgo.InteractionResponsePong})
+		return
+	}
+
+	timeout := srv.ResponseTimeout
+	if timeout == 0 {
+		timeout = DefaultInteractionResponseTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	hr := &httpResponder{
+		session:     srv.Session,
+		interaction: &interaction,
+		responses:   make(chan *discordgo.InteractionResponse, 1),
+	}
+	go srv.handle(detachedContext{req.Context()}, hr)
+
+	select {
+	case response := <-hr.responses:
+		writeInteractionResponse(w, response)
+	case <-timer.C:
+		if response := hr.acknowledge(); response != nil {
+			writeInteractionResponse(w, response)
+		} else {
+			// NOTE: the handler has responded right before the timeout.
+			writeInteractionResponse(w, <-hr.responses)
+		}
+	}
+}
+
+// handle routes the interaction. If no handler has received it, or the handler has panicked,
+// the failure is reported to the user right away, instead of letting the interaction be deferred and never completed.
+func (srv *InteractionServer) handle(c context.Context, hr *httpResponder) {
+	defer func() {
+		if v := recover(); v != nil {
+			srv.Router.logger().Error("interaction handler panicked", interactionFields(hr.interaction, "panic", v)...)
+			_ = hr.respond(hr.failure())
+		}
+	}()
+	if !srv.Router.handleInteraction(c, srv.Session, hr.interaction, hr.respond) {
+		_ = hr.respond(hr.failure())
+	}
+}
+
+// detachedContext keeps the values of the request context (e.g. its span), so the spans of the invocation nest under it,
+// but not its cancellation, since the handler may outlive the request, once the interaction is deferred.
+type detachedContext struct{ context.Context }
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func writeInteractionResponse(w http.ResponseWriter, response *discordgo.InteractionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// httpResponder passes the initial response of the handler to the HTTP response,
+// and turns responses sent after the acknowledgement into edits of the original response.
+type httpResponder struct {
+	session     *discordgo.Session
+	interaction *discordgo.Interaction
+	responses   chan *discordgo.InteractionResponse
+
+	mtx          sync.Mutex
+	responded    bool
+	acknowledged bool
+}
+
+func (hr *httpResponder) respond(response *discordgo.InteractionResponse) error {
+	hr.mtx.Lock()
+	defer hr.mtx.Unlock()
+
+	switch {
+	case hr.responded:
+		return ErrAlreadyResponded
+	case hr.acknowledged:
+		hr.responded = true
+		return hr.edit(response)
+	}
+
+	if response.Data != nil && len(response.Data.Files) != 0 {
+		return ErrFilesInHTTPResponse
+	}
+	hr.responded = true
+	hr.responses <- response
+	return nil
+}
+
+// acknowledge returns the deferred response for the interaction, if the handler has not responded yet.
+func (hr *httpResponder) acknowledge() *discordgo.InteractionResponse {
+	hr.mtx.Lock()
+	defer hr.mtx.Unlock()
+
+	if hr.responded {
+		return nil
+	}
+	hr.acknowledged = true
+
+	switch hr.interaction.Type {
+	case discordgo.InteractionMessageComponent:
+		return &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate}
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		hr.responded = true
+		return &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{Choices: []*discordgo.ApplicationCommandOptionChoice{}},
+		}
+	}
+	return &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource}
+}
+
+// failure returns the response for the interaction, which could not be handled.
+func (hr *httpResponder) failure() *discordgo.InteractionResponse {
+	if hr.interaction.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		return &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{Choices: []*discordgo.ApplicationCommandOptionChoice{}},
+		}
+	}
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: capitalize(ErrInteractionFailed.Error()) + ".",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}
+}
+
+// edit applies the response to the acknowledged interaction.
+func (hr *httpResponder) edit(response *discordgo.InteractionResponse) error {
+	switch response.Type {
+	case discordgo.InteractionResponseDeferredChannelMessageWithSource, discordgo.InteractionResponseDeferredMessageUpdate:
+		return nil
+	case discordgo.InteractionResponseChannelMessageWithSource, discordgo.InteractionResponseUpdateMessage:
+	default:
+		return ErrAlreadyResponded
+	}
+
+	edit := &discordgo.WebhookEdit{}
+	if data := response.Data; data != nil {
+		edit.Content = &data.Content
+		edit.AllowedMentions = data.AllowedMentions
+		edit.Files = data.Files
+		if data.Embeds != nil {
+			edit.Embeds = &data.Embeds
+		}
+		if data.Components != nil {
+			edit.Components = &data.Components
+		}
+	}
+	_, err := hr.session.InteractionResponseEdit(hr.interaction, edit)
+	return err
+}
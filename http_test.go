@@ -0,0 +1,142 @@
+package disgolf_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func signedRequest(t *testing.T, key ed25519.PrivateKey, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewBufferString(body))
+	timestamp := "1700000000"
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(key, []byte(timestamp+body))))
+	return req
+}
+
+func serve(t *testing.T, srv http.Handler, req *http.Request) (int, *discordgo.InteractionResponse) {
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	var response discordgo.InteractionResponse
+	if rec.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	}
+	return rec.Code, &response
+}
+
+func TestInteractionServer(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name: "ping",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+				assert.NoError(t, ctx.Respond(&discordgo.InteractionResponse{
+					Type: discordgo.InteractionResponseChannelMessageWithSource,
+					Data: &discordgo.InteractionResponseData{Content: "pong"},
+				}))
+			}),
+		},
+		{
+			Name:    "slow",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {}),
+		},
+		{
+			Name:    "crash",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { panic("crash") }),
+		},
+	})
+	r.RegisterComponent("button", disgolf.ComponentHandlerFunc(func(ctx *disgolf.ComponentCtx) {
+		_ = ctx.Respond(&discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{Content: "clicked " + ctx.Arguments},
+		})
+	}))
+
+	srv, err := disgolf.NewInteractionServer(r, &discordgo.Session{}, hex.EncodeToString(public))
+	if !assert.NoError(t, err) {
+		return
+	}
+	srv.ResponseTimeout = 50 * time.Millisecond
+
+	code, response := serve(t, srv, signedRequest(t, private, `{"type":1}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, discordgo.InteractionResponsePong, response.Type)
+
+	req := signedRequest(t, private, `{"type":1}`)
+	req.Header.Set("X-Signature-Timestamp", "0")
+	code, _ = serve(t, srv, req)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, response = serve(t, srv, signedRequest(t, private, `{"type":2,"data":{"name":"ping"}}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, discordgo.InteractionResponseChannelMessageWithSource, response.Type)
+	assert.Equal(t, "pong", response.Data.Content)
+
+	code, response = serve(t, srv, signedRequest(t, private, `{"type":3,"data":{"custom_id":"button:42","component_type":2}}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "clicked 42", response.Data.Content)
+
+	code, response = serve(t, srv, signedRequest(t, private, `{"type":2,"data":{"name":"slow"}}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, discordgo.InteractionResponseDeferredChannelMessageWithSource, response.Type)
+
+	for _, body := range []string{
+		`{"type":2,"data":{"name":"crash"}}`,
+		`{"type":2,"data":{"name":"unknown"}}`,
+		`{"type":3,"data":{"custom_id":"unknown","component_type":2}}`,
+	} {
+		code, response = serve(t, srv, signedRequest(t, private, body))
+		assert.Equal(t, http.StatusOK, code, body)
+		assert.Equal(t, discordgo.InteractionResponseChannelMessageWithSource, response.Type, body)
+		assert.Equal(t, "This interaction could not be handled.", response.Data.Content, body)
+		assert.Equal(t, discordgo.MessageFlagsEphemeral, response.Data.Flags, body)
+	}
+}
+
+func TestInteractionServer_Executor(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+	pool := disgolf.NewWorkerPool(1, 0)
+	defer pool.Close()
+
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name:    "crash",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { panic("crash") }),
+		},
+		{
+			Name:    "ping",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { _ = ctx.ReplyText("pong") }),
+		},
+	})
+	r.Executor = pool
+	srv, err := disgolf.NewInteractionServer(r, &discordgo.Session{}, hex.EncodeToString(public))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	code, response := serve(t, srv, signedRequest(t, private, `{"type":2,"data":{"name":"crash"}}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "This interaction could not be handled.", response.Data.Content)
+
+	// NOTE: the worker survives the panic.
+	code, response = serve(t, srv, signedRequest(t, private, `{"type":2,"data":{"name":"ping"}}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "pong", response.Data.Content)
+}
@@ -0,0 +1,278 @@
+package disgolf
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/yaml.v3"
+)
+
+// Plural forms, as defined by CLDR.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralForm returns the plural form of the number in the locale.
+func PluralForm(locale discordgo.Locale, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+
+	switch locale {
+	case discordgo.Russian, discordgo.Ukrainian:
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		}
+		return PluralMany
+	case discordgo.Croatian:
+		// NOTE: unlike Russian and Ukrainian, Croatian has no "many" form in CLDR, "other" is used instead.
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		}
+		return PluralOther
+	case discordgo.Polish:
+		switch {
+		case n == 1:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		}
+		return PluralMany
+	case discordgo.Czech:
+		switch {
+		case n == 1:
+			return PluralOne
+		case n >= 2 && n <= 4:
+			return PluralFew
+		}
+		return PluralOther
+	case discordgo.Lithuanian:
+		switch {
+		case mod10 == 1 && (mod100 < 11 || mod100 > 19):
+			return PluralOne
+		case mod10 >= 2 && (mod100 < 11 || mod100 > 19):
+			return PluralFew
+		}
+		return PluralOther
+	case discordgo.Romanian:
+		switch {
+		case n == 1:
+			return PluralOne
+		case n == 0 || (mod100 >= 2 && mod100 <= 19):
+			return PluralFew
+		}
+		return PluralOther
+	case discordgo.French, discordgo.PortugueseBR:
+		if n == 0 || n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	case discordgo.ChineseCN, discordgo.ChineseTW, discordgo.Japanese, discordgo.Korean, discordgo.Thai, discordgo.Vietnamese:
+		return PluralOther
+	}
+
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// Message is a translated message. Key is the plural form, value is the text.
+// Messages without plural forms only have PluralOther form.
+//
+// In catalog files, a message is either a string or an object of plural forms.
+type Message map[string]string
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*m = Message{PluralOther: text}
+		return nil
+	}
+	return json.Unmarshal(data, (*map[string]string)(m))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface.
+func (m *Message) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*m = Message{PluralOther: value.Value}
+		return nil
+	}
+	return value.Decode((*map[string]string)(m))
+}
+
+// MessageCatalog stores translated messages for the responses.
+type MessageCatalog struct {
+	mtx      sync.RWMutex
+	messages map[discordgo.Locale]map[string]Message
+}
+
+// NewMessageCatalog constructs an empty message catalog.
+func NewMessageCatalog() *MessageCatalog {
+	return &MessageCatalog{messages: make(map[discordgo.Locale]map[string]Message)}
+}
+
+// LoadMessageCatalog loads messages from the directory.
+// Each file in the directory contains messages for a single locale, and is named after it (e.g. ru.json, es-ES.yaml).
+// Supported formats are JSON (.json) and YAML (.yaml, .yml). Files with other extensions are ignored.
+func LoadMessageCatalog(dir string) (*MessageCatalog, error) {
+	catalog := NewMessageCatalog()
+	if err := loadLocaleFiles(dir, catalog.Parse); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// Parse parses messages for the locale and adds them to the catalog.
+// Format is either "json", "yaml" or "yml".
+func (c *MessageCatalog) Parse(locale discordgo.Locale, data []byte, format string) error {
+	messages := make(map[string]Message)
+	if err := decodeLocaleFile(locale, data, format, &messages); err != nil {
+		return err
+	}
+
+	for key, message := range messages {
+		c.Set(locale, key, message)
+	}
+	return nil
+}
+
+// Set adds the message to the catalog.
+func (c *MessageCatalog) Set(locale discordgo.Locale, key string, message Message) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]Message)
+	}
+	c.messages[locale][key] = message
+}
+
+// Get returns the message from the catalog.
+func (c *MessageCatalog) Get(locale discordgo.Locale, key string) (Message, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	message, ok := c.messages[locale][key]
+	return message, ok
+}
+
+// I18n is a configuration of response translations.
+type I18n struct {
+	Catalog *MessageCatalog
+	// DefaultLocale is used when there are no translations for user and guild locales. Defaults to discordgo.EnglishUS.
+	DefaultLocale discordgo.Locale
+	// GuildLocale returns the locale override for the guild. If it returns empty locale, the guild locale provided by Discord is used.
+	// It is the only source of guild locale for message commands, since Discord provides no locale for them.
+	GuildLocale func(guildID string) discordgo.Locale
+}
+
+func (i *I18n) translator(userLocale discordgo.Locale, guildID string, guildLocale *discordgo.Locale) *Translator {
+	if i == nil {
+		return nil
+	}
+
+	var locales []discordgo.Locale
+	if userLocale != "" {
+		locales = append(locales, userLocale)
+	}
+	if guildID != "" && i.GuildLocale != nil {
+		if locale := i.GuildLocale(guildID); locale != "" {
+			guildLocale = &locale
+		}
+	}
+	if guildLocale != nil && *guildLocale != "" {
+		locales = append(locales, *guildLocale)
+	}
+	if i.DefaultLocale != "" {
+		locales = append(locales, i.DefaultLocale)
+	} else {
+		locales = append(locales, discordgo.EnglishUS)
+	}
+
+	return &Translator{Catalog: i.Catalog, Locales: locales}
+}
+
+// Translator translates messages into the locale of an invocation.
+// A nil translator returns message keys as they are.
+type Translator struct {
+	Catalog *MessageCatalog
+	// Locales in the order of preference.
+	Locales []discordgo.Locale
+}
+
+// Vars are values of message placeholders. Placeholders are written as {name} in messages.
+type Vars map[string]interface{}
+
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+func format(text string, vars Vars) string {
+	if len(vars) == 0 {
+		return text
+	}
+	return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if value, ok := vars[placeholder[1:len(placeholder)-1]]; ok {
+			return fmt.Sprint(value)
+		}
+		return placeholder
+	})
+}
+
+// Locale returns the most preferred locale.
+func (t *Translator) Locale() discordgo.Locale {
+	if t == nil || len(t.Locales) == 0 {
+		return discordgo.EnglishUS
+	}
+	return t.Locales[0]
+}
+
+func (t *Translator) lookup(key string, n int) string {
+	if t != nil {
+		for _, locale := range t.Locales {
+			message, ok := t.Catalog.Get(locale, key)
+			if !ok {
+				continue
+			}
+			if text, ok := message[PluralForm(locale, n)]; ok {
+				return text
+			}
+			if text, ok := message[PluralOther]; ok {
+				return text
+			}
+		}
+	}
+	return key
+}
+
+// T translates the message and fills its placeholders. If the message has no translations, the key is returned.
+func (t *Translator) T(key string, vars Vars) string {
+	return format(t.lookup(key, 0), vars)
+}
+
+// N translates the message in the plural form of n and fills its placeholders. The {count} placeholder is set to n.
+func (t *Translator) N(key string, n int, vars Vars) string {
+	all := Vars{"count": n}
+	for k, v := range vars {
+		all[k] = v
+	}
+	return format(t.lookup(key, n), all)
+}
@@ -0,0 +1,70 @@
+package disgolf_test
+
+import (
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluralForm(t *testing.T) {
+	forms := map[int]string{1: disgolf.PluralOne, 3: disgolf.PluralFew, 5: disgolf.PluralMany, 11: disgolf.PluralMany, 21: disgolf.PluralOne, 22: disgolf.PluralFew}
+	for n, form := range forms {
+		assert.Equal(t, form, disgolf.PluralForm(discordgo.Russian, n), n)
+	}
+	assert.Equal(t, disgolf.PluralOne, disgolf.PluralForm(discordgo.Croatian, 21))
+	assert.Equal(t, disgolf.PluralFew, disgolf.PluralForm(discordgo.Croatian, 3))
+	assert.Equal(t, disgolf.PluralOther, disgolf.PluralForm(discordgo.Croatian, 5))
+	assert.Equal(t, disgolf.PluralOther, disgolf.PluralForm(discordgo.Croatian, 11))
+	assert.Equal(t, disgolf.PluralOne, disgolf.PluralForm(discordgo.German, 1))
+	assert.Equal(t, disgolf.PluralOther, disgolf.PluralForm(discordgo.German, 0))
+	assert.Equal(t, disgolf.PluralOther, disgolf.PluralForm(discordgo.Japanese, 1))
+}
+
+func TestTranslator(t *testing.T) {
+	catalog := disgolf.NewMessageCatalog()
+	assert.NoError(t, catalog.Parse(discordgo.Russian, []byte(`{
+		"greeting": "Привет, {name}!",
+		"coins": {"one": "{count} монета", "few": "{count} монеты", "many": "{count} монет"}
+	}`), "json"))
+	assert.NoError(t, catalog.Parse(discordgo.EnglishUS, []byte(`
+greeting: Hello, {name}!
+farewell: Bye!
+coins:
+  one: "{count} coin"
+  other: "{count} coins"
+`), "yaml"))
+
+	var translator *disgolf.Translator
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name:    "balance",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { translator = ctx.Translator() }),
+	}})
+	r.I18n = &disgolf.I18n{Catalog: catalog}
+
+	guildLocale := discordgo.Russian
+	r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:        discordgo.InteractionApplicationCommand,
+		GuildID:     "guild",
+		Locale:      discordgo.SpanishES,
+		GuildLocale: &guildLocale,
+		Data:        discordgo.ApplicationCommandInteractionData{Name: "balance"},
+	}})
+
+	assert.Equal(t, discordgo.SpanishES, translator.Locale())
+	assert.Equal(t, "Привет, Фёдор!", translator.T("greeting", disgolf.Vars{"name": "Фёдор"}))
+	assert.Equal(t, "Bye!", translator.T("farewell", nil))
+	assert.Equal(t, "missing", translator.T("missing", nil))
+	assert.Equal(t, "22 монеты", translator.N("coins", 22, nil))
+	assert.Equal(t, "5 монет", translator.N("coins", 5, nil))
+
+	r.I18n.GuildLocale = func(guildID string) discordgo.Locale { return discordgo.EnglishUS }
+	r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:        discordgo.InteractionApplicationCommand,
+		GuildID:     "guild",
+		GuildLocale: &guildLocale,
+		Data:        discordgo.ApplicationCommandInteractionData{Name: "balance"},
+	}})
+	assert.Equal(t, "1 coin", translator.N("coins", 1, nil))
+}
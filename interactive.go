@@ -0,0 +1,141 @@
+package disgolf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// interactiveMessage is a message with components, sent in reply to an invocation (e.g. by Paginator).
+// It hides the differences between replies to slash and message commands.
+type interactiveMessage struct {
+	inv Invocation
+	// nonce is unique for the invocation. It prefixes the custom ids of the components.
+	nonce   string
+	message *discordgo.Message
+}
+
+func newInteractiveMessage(inv Invocation, name string) (*interactiveMessage, error) {
+	var id string
+	switch ctx := inv.(type) {
+	case *Ctx:
+		id = ctx.Interaction.ID
+	case *MessageCtx:
+		id = ctx.Message.ID
+	default:
+		return nil, fmt.Errorf("unsupported invocation type: %T", inv)
+	}
+	return &interactiveMessage{inv: inv, nonce: ComponentCustomID(name, id)}, nil
+}
+
+// customID makes the custom id of a component of the message.
+func (m *interactiveMessage) customID(action string) string {
+	return m.nonce + ":" + action
+}
+
+// action returns the action of the component, which custom id was made by customID.
+func (m *interactiveMessage) action(ctx *ComponentCtx) string {
+	return strings.TrimPrefix(ctx.Data.CustomID, m.nonce+":")
+}
+
+// send sends the message. Slash commands are responded to, or followed up, if they were already responded to.
+func (m *interactiveMessage) send(data *discordgo.InteractionResponseData) (err error) {
+	switch ctx := m.inv.(type) {
+	case *Ctx:
+		if !ctx.Responded() {
+			return ctx.Respond(&discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: data,
+			})
+		}
+		m.message, err = ctx.Session.FollowupMessageCreate(ctx.Interaction, true, &discordgo.WebhookParams{
+			Content:    data.Content,
+			Embeds:     data.Embeds,
+			Components: data.Components,
+			Flags:      data.Flags,
+		})
+	case *MessageCtx:
+		m.message, err = ctx.ReplyComplex(&discordgo.MessageSend{
+			Content:    data.Content,
+			Embeds:     data.Embeds,
+			Components: data.Components,
+		}, false)
+	}
+	return err
+}
+
+// edit replaces the content, embeds and components of the sent message.
+func (m *interactiveMessage) edit(data *discordgo.InteractionResponseData) (err error) {
+	switch ctx := m.inv.(type) {
+	case *Ctx:
+		edit := &discordgo.WebhookEdit{
+			Content:    &data.Content,
+			Embeds:     &data.Embeds,
+			Components: &data.Components,
+		}
+		if m.message == nil {
+			_, err = ctx.Session.InteractionResponseEdit(ctx.Interaction, edit)
+		} else {
+			_, err = ctx.Session.FollowupMessageEdit(ctx.Interaction, m.message.ID, edit)
+		}
+	case *MessageCtx:
+		_, err = ctx.Session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         m.message.ID,
+			Channel:    m.message.ChannelID,
+			Content:    &data.Content,
+			Embeds:     data.Embeds,
+			Components: data.Components,
+		})
+	}
+	return err
+}
+
+// collect starts collecting interactions with the components of the message. The collector stops, when c ends.
+// Helpers handling the interactions in background must not pass the context of the invocation,
+// since it may be cancelled once the handler returns (e.g. by ConcurrencyLimit).
+func (m *interactiveMessage) collect(c context.Context, cfg ComponentCollectorConfig) *ComponentCollector {
+	prefix := m.nonce + ":"
+	cfg.Filter = func(ctx *ComponentCtx) bool { return strings.HasPrefix(ctx.Data.CustomID, prefix) }
+	return newComponentCollector(invocationRouter(m.inv), c, cfg)
+}
+
+// rejectForeignUser responds to the interaction of a user other than the author of the invocation with an ephemeral notice.
+// It returns true, if the interaction was rejected.
+func (m *interactiveMessage) rejectForeignUser(ctx *ComponentCtx) bool {
+	author, user := m.inv.Author(), ctx.Author()
+	if author == nil || user == nil || author.ID == user.ID {
+		return false
+	}
+	_ = ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: ErrAuthorOnly.Error(),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	return true
+}
+
+// disableComponents returns a copy of the components with all buttons and select menus disabled.
+func disableComponents(components []discordgo.MessageComponent) []discordgo.MessageComponent {
+	disabled := make([]discordgo.MessageComponent, len(components))
+	for i, component := range components {
+		switch c := component.(type) {
+		case discordgo.ActionsRow:
+			c.Components = disableComponents(c.Components)
+			disabled[i] = c
+		case discordgo.Button:
+			// NOTE: link buttons do not have interactions, so they are kept enabled.
+			c.Disabled = c.Style != discordgo.LinkButton
+			disabled[i] = c
+		case discordgo.SelectMenu:
+			c.Disabled = true
+			disabled[i] = c
+		default:
+			disabled[i] = component
+		}
+	}
+	return disabled
+}
@@ -0,0 +1,308 @@
+package disgolf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/yaml.v3"
+)
+
+// CommandTranslation is a translation of a command, its options and subcommands into a single locale.
+type CommandTranslation struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	// Options is a map of option translations. Key is the option name.
+	Options map[string]*OptionTranslation `json:"options" yaml:"options"`
+	// SubCommands is a map of subcommand translations. Key is the subcommand name.
+	SubCommands map[string]*CommandTranslation `json:"subcommands" yaml:"subcommands"`
+}
+
+// OptionTranslation is a translation of a command option into a single locale.
+type OptionTranslation struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	// Choices is a map of choice name translations. Key is the choice name.
+	Choices map[string]string `json:"choices" yaml:"choices"`
+	// Options is a map of translations of nested options. Key is the option name.
+	Options map[string]*OptionTranslation `json:"options" yaml:"options"`
+}
+
+// LocalizationCatalog stores translations of commands.
+// Key is the locale, value is a map of command translations, where key is the command name.
+type LocalizationCatalog map[discordgo.Locale]map[string]*CommandTranslation
+
+// LoadLocalizationCatalog loads translations from the directory.
+// Each file in the directory contains translations for a single locale, and is named after it (e.g. ru.json, es-ES.yaml).
+// Supported formats are JSON (.json) and YAML (.yaml, .yml). Files with other extensions are ignored.
+func LoadLocalizationCatalog(dir string) (LocalizationCatalog, error) {
+	catalog := make(LocalizationCatalog)
+	if err := loadLocaleFiles(dir, catalog.Parse); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// decodeLocaleFile checks the locale, and decodes the data in the format ("json", "yaml" or "yml") into v.
+func decodeLocaleFile(locale discordgo.Locale, data []byte, format string, v interface{}) error {
+	if _, ok := discordgo.Locales[locale]; !ok {
+		return fmt.Errorf("unknown locale %q", locale)
+	}
+	switch format {
+	case "json":
+		return json.Unmarshal(data, v)
+	case "yaml", "yml":
+		return yaml.Unmarshal(data, v)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// loadLocaleFiles passes the contents of each JSON or YAML file in the directory to parse,
+// along with the locale the file is named after and its format. Files with other extensions are ignored.
+func loadLocaleFiles(dir string, parse func(locale discordgo.Locale, data []byte, format string) error) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(file.Name())
+		if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return err
+		}
+
+		locale := discordgo.Locale(strings.TrimSuffix(file.Name(), ext))
+		if err = parse(locale, data, ext[1:]); err != nil {
+			return fmt.Errorf("%s: %w", file.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Parse parses translations for the locale and adds them to the catalog.
+// Format is either "json", "yaml" or "yml".
+func (c LocalizationCatalog) Parse(locale discordgo.Locale, data []byte, format string) error {
+	translations := make(map[string]*CommandTranslation)
+	if err := decodeLocaleFile(locale, data, format, &translations); err != nil {
+		return err
+	}
+
+	if c[locale] == nil {
+		c[locale] = translations
+		return nil
+	}
+	for name, translation := range translations {
+		c[locale][name] = translation
+	}
+	return nil
+}
+
+// Apply sets localizations of the commands in the router (including subcommands, options and choices)
+// from the catalog, and validates them.
+func (c LocalizationCatalog) Apply(r *Router) error {
+	for locale, translations := range c {
+		applyCommandTranslations(r, locale, translations)
+	}
+
+	var errs LocalizationErrors
+	for _, cmd := range r.List() {
+		errs = append(errs, validateCommandLocalizations(cmd, nil, cmd.Type)...)
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func applyCommandTranslations(r *Router, locale discordgo.Locale, translations map[string]*CommandTranslation) {
+	for _, cmd := range r.List() {
+		translation, ok := translations[cmd.Name]
+		if !ok || translation == nil {
+			continue
+		}
+		if translation.Name != "" {
+			if cmd.NameLocalizations == nil {
+				cmd.NameLocalizations = make(map[discordgo.Locale]string)
+			}
+			cmd.NameLocalizations[locale] = translation.Name
+		}
+		if translation.Description != "" {
+			if cmd.DescriptionLocalizations == nil {
+				cmd.DescriptionLocalizations = make(map[discordgo.Locale]string)
+			}
+			cmd.DescriptionLocalizations[locale] = translation.Description
+		}
+		applyOptionTranslations(cmd.Options, locale, translation.Options)
+		applyCommandTranslations(cmd.SubCommands, locale, translation.SubCommands)
+	}
+}
+
+func applyOptionTranslations(options []*discordgo.ApplicationCommandOption, locale discordgo.Locale, translations map[string]*OptionTranslation) {
+	for _, opt := range options {
+		translation, ok := translations[opt.Name]
+		if !ok || translation == nil {
+			continue
+		}
+		if translation.Name != "" {
+			if opt.NameLocalizations == nil {
+				opt.NameLocalizations = make(map[discordgo.Locale]string)
+			}
+			opt.NameLocalizations[locale] = translation.Name
+		}
+		if translation.Description != "" {
+			if opt.DescriptionLocalizations == nil {
+				opt.DescriptionLocalizations = make(map[discordgo.Locale]string)
+			}
+			opt.DescriptionLocalizations[locale] = translation.Description
+		}
+		for _, choice := range opt.Choices {
+			if name, ok := translation.Choices[choice.Name]; ok {
+				if choice.NameLocalizations == nil {
+					choice.NameLocalizations = make(map[discordgo.Locale]string)
+				}
+				choice.NameLocalizations[locale] = name
+			}
+		}
+		applyOptionTranslations(opt.Options, locale, translation.Options)
+	}
+}
+
+// LocalizationError describes an invalid localization.
+type LocalizationError struct {
+	// Path to the localized entity (command, option or choice names, starting from the top-level command).
+	Path   []string
+	Locale discordgo.Locale
+	Err    error
+}
+
+func (e *LocalizationError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", strings.Join(e.Path, " "), e.Locale, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *LocalizationError) Unwrap() error {
+	return e.Err
+}
+
+// LocalizationErrors is a list of localization errors.
+type LocalizationErrors []*LocalizationError
+
+func (e LocalizationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+var chatInputNamePattern = regexp.MustCompile(`^[-_\p{L}\p{N}\p{Devanagari}\p{Thai}]{1,32}$`)
+
+// ValidateName checks whether the name satisfies Discord naming rules for commands of the type.
+// Options and subcommands follow the rules of chat input commands.
+func ValidateName(name string, typ discordgo.ApplicationCommandType) error {
+	if typ == discordgo.UserApplicationCommand || typ == discordgo.MessageApplicationCommand {
+		if n := utf8.RuneCountInString(name); n < 1 || n > 32 {
+			return fmt.Errorf("name %q must be 1-32 characters long", name)
+		}
+		return nil
+	}
+
+	if !chatInputNamePattern.MatchString(name) {
+		return fmt.Errorf("name %q must be 1-32 characters long and contain only letters, numbers, dashes and underscores", name)
+	}
+	if strings.ToLower(name) != name {
+		return fmt.Errorf("name %q must be lowercase", name)
+	}
+	return nil
+}
+
+func validateDescription(description string) error {
+	if n := utf8.RuneCountInString(description); n < 1 || n > 100 {
+		return fmt.Errorf("description must be 1-100 characters long")
+	}
+	return nil
+}
+
+// ValidateLocalizations checks localizations of the command, its options, choices and subcommands.
+// Localized names must satisfy the same naming rules as the original ones.
+func ValidateLocalizations(cmd *Command) error {
+	if errs := validateCommandLocalizations(cmd, nil, cmd.Type); len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateLocale(path []string, locale discordgo.Locale) *LocalizationError {
+	if _, ok := discordgo.Locales[locale]; !ok {
+		return &LocalizationError{Path: path, Locale: locale, Err: fmt.Errorf("unknown locale")}
+	}
+	return nil
+}
+
+func validateCommandLocalizations(cmd *Command, parent []string, typ discordgo.ApplicationCommandType) (errs LocalizationErrors) {
+	path := append(parent[:len(parent):len(parent)], cmd.Name)
+	for locale, name := range cmd.NameLocalizations {
+		if err := validateLocale(path, locale); err != nil {
+			errs = append(errs, err)
+		} else if err := ValidateName(name, typ); err != nil {
+			errs = append(errs, &LocalizationError{Path: path, Locale: locale, Err: err})
+		}
+	}
+	for locale, description := range cmd.DescriptionLocalizations {
+		if err := validateLocale(path, locale); err != nil {
+			errs = append(errs, err)
+		} else if err := validateDescription(description); err != nil {
+			errs = append(errs, &LocalizationError{Path: path, Locale: locale, Err: err})
+		}
+	}
+	errs = append(errs, validateOptionLocalizations(cmd.Options, path)...)
+	for _, subcommand := range cmd.SubCommands.List() {
+		errs = append(errs, validateCommandLocalizations(subcommand, path, discordgo.ChatApplicationCommand)...)
+	}
+	return
+}
+
+func validateOptionLocalizations(options []*discordgo.ApplicationCommandOption, parent []string) (errs LocalizationErrors) {
+	for _, opt := range options {
+		path := append(parent[:len(parent):len(parent)], opt.Name)
+		for locale, name := range opt.NameLocalizations {
+			if err := validateLocale(path, locale); err != nil {
+				errs = append(errs, err)
+			} else if err := ValidateName(name, discordgo.ChatApplicationCommand); err != nil {
+				errs = append(errs, &LocalizationError{Path: path, Locale: locale, Err: err})
+			}
+		}
+		for locale, description := range opt.DescriptionLocalizations {
+			if err := validateLocale(path, locale); err != nil {
+				errs = append(errs, err)
+			} else if err := validateDescription(description); err != nil {
+				errs = append(errs, &LocalizationError{Path: path, Locale: locale, Err: err})
+			}
+		}
+		for _, choice := range opt.Choices {
+			for locale, name := range choice.NameLocalizations {
+				choicePath := append(path[:len(path):len(path)], choice.Name)
+				if err := validateLocale(choicePath, locale); err != nil {
+					errs = append(errs, err)
+				} else if n := utf8.RuneCountInString(name); n < 1 || n > 100 {
+					errs = append(errs, &LocalizationError{Path: choicePath, Locale: locale, Err: fmt.Errorf("choice name must be 1-100 characters long")})
+				}
+			}
+		}
+		errs = append(errs, validateOptionLocalizations(opt.Options, path)...)
+	}
+	return
+}
@@ -0,0 +1,69 @@
+package disgolf_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalizationCatalog(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ru.json"), []byte(`{
+		"weather": {
+			"name": "погода",
+			"description": "Узнать погоду",
+			"options": {"units": {"name": "единицы", "choices": {"Celsius": "Цельсий"}}},
+			"subcommands": {"today": {"name": "сегодня", "description": "Погода на сегодня"}}
+		}
+	}`), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "de.yaml"), []byte(`
+weather:
+  name: wetter
+  description: Wetter abrufen
+`), 0644))
+
+	catalog, err := disgolf.LoadLocalizationCatalog(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	units := &discordgo.ApplicationCommandOption{
+		Name:    "units",
+		Type:    discordgo.ApplicationCommandOptionString,
+		Choices: []*discordgo.ApplicationCommandOptionChoice{{Name: "Celsius", Value: "c"}},
+	}
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name:        "weather",
+		Description: "Get the weather",
+		Options:     []*discordgo.ApplicationCommandOption{units},
+		SubCommands: disgolf.NewRouter([]*disgolf.Command{{Name: "today", Description: "Today's weather"}}),
+	}})
+	assert.NoError(t, catalog.Apply(r))
+
+	cmd := r.Get("weather").ApplicationCommand()
+	assert.Equal(t, map[discordgo.Locale]string{discordgo.Russian: "погода", discordgo.German: "wetter"}, *cmd.NameLocalizations)
+	assert.Equal(t, "Wetter abrufen", (*cmd.DescriptionLocalizations)[discordgo.German])
+	assert.Equal(t, "единицы", units.NameLocalizations[discordgo.Russian])
+	assert.Equal(t, "Цельсий", units.Choices[0].NameLocalizations[discordgo.Russian])
+	assert.Equal(t, "сегодня", cmd.Options[1].NameLocalizations[discordgo.Russian])
+}
+
+func TestValidateLocalizations(t *testing.T) {
+	cmd := &disgolf.Command{
+		Name:              "weather",
+		Description:       "Get the weather",
+		NameLocalizations: map[discordgo.Locale]string{discordgo.SpanishES: "El Tiempo"},
+	}
+	err := disgolf.ValidateLocalizations(cmd)
+	if assert.IsType(t, disgolf.LocalizationErrors{}, err) {
+		assert.Len(t, err, 1)
+		assert.Equal(t, discordgo.SpanishES, err.(disgolf.LocalizationErrors)[0].Locale)
+	}
+
+	cmd.NameLocalizations[discordgo.SpanishES] = "el_tiempo"
+	assert.NoError(t, disgolf.ValidateLocalizations(cmd))
+}
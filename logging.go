@@ -0,0 +1,83 @@
+package disgolf
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Logger is a structured logger. Arguments are alternating keys and values.
+// It is compatible with *slog.Logger from log/slog package.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+func (r *Router) logger() Logger {
+	if r.Logger == nil {
+		return nopLogger{}
+	}
+	return r.Logger
+}
+
+// interactionFields returns log fields describing the source of the interaction.
+func interactionFields(i *discordgo.Interaction, args ...interface{}) []interface{} {
+	user := i.User
+	if i.Member != nil {
+		user = i.Member.User
+	}
+	fields := []interface{}{"interaction", i.ID, "guild", i.GuildID, "channel", i.ChannelID}
+	if user != nil {
+		fields = append(fields, "user", user.ID)
+	}
+	return append(fields, args...)
+}
+
+// messageFields returns log fields describing the source of the message.
+func messageFields(m *discordgo.Message, args ...interface{}) []interface{} {
+	fields := []interface{}{"message", m.ID, "guild", m.GuildID, "channel", m.ChannelID}
+	if m.Author != nil {
+		fields = append(fields, "user", m.Author.ID)
+	}
+	return append(fields, args...)
+}
+
+// logDispatch logs the end of the dispatch. stopped is the middleware, which has not called the next handler, if any.
+func logDispatch(l Logger, path []string, start time.Time, stopped interface{}, fields []interface{}) {
+	fields = append(fields, "command", strings.Join(path, " "), "latency", time.Since(start))
+	if stopped != nil {
+		l.Info("middleware stopped the dispatch", append(fields, "middleware", handlerName(stopped))...)
+		return
+	}
+	l.Info("command handled", fields...)
+}
+
+// handlerName describes the handler or middleware in logs and traces. Middleware adapters are unwrapped,
+// and functions (e.g. HandlerFunc or Check) are named after the function itself, since their types are not descriptive.
+func handlerName(h interface{}) string {
+	switch adapter := h.(type) {
+	case commandMiddleware:
+		h = adapter.Middleware
+	case messageCommandMiddleware:
+		h = adapter.Middleware
+	}
+	if v := reflect.ValueOf(h); v.Kind() == reflect.Func && !v.IsNil() {
+		if f := runtime.FuncForPC(v.Pointer()); f != nil {
+			return f.Name()
+		}
+	}
+	return fmt.Sprintf("%T", h)
+}
@@ -0,0 +1,92 @@
+package disgolf_test
+
+import (
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+type logEntry struct {
+	level, msg string
+	fields     map[string]interface{}
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) log(level, msg string, args []interface{}) {
+	fields := make(map[string]interface{})
+	for i := 0; i+1 < len(args); i += 2 {
+		fields[args[i].(string)] = args[i+1]
+	}
+	l.entries = append(l.entries, logEntry{level, msg, fields})
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.log("debug", msg, args) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.log("info", msg, args) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.log("warn", msg, args) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.log("error", msg, args) }
+
+func TestRouter_Logger(t *testing.T) {
+	logger := &testLogger{}
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name:    "ping",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {}),
+		},
+		{
+			Name:        "secret",
+			Middlewares: []disgolf.Handler{disgolf.HandlerFunc(stopCommand)},
+			Handler:     disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {}),
+		},
+		{
+			Name:              "guarded",
+			CommonMiddlewares: []disgolf.Middleware{disgolf.MiddlewareFunc(stopInvocation)},
+			Handler:           disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {}),
+		},
+	})
+	r.Logger = logger
+
+	dispatch := func(name string) {
+		r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			ID:      "interaction",
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "guild",
+			Member:  &discordgo.Member{User: &discordgo.User{ID: "user"}},
+			Data:    discordgo.ApplicationCommandInteractionData{Name: name},
+		}})
+	}
+	dispatch("ping")
+	dispatch("secret")
+	dispatch("guarded")
+	dispatch("unknown")
+
+	var messages []string
+	for _, entry := range logger.entries {
+		messages = append(messages, entry.level+": "+entry.msg)
+	}
+	assert.Equal(t, []string{
+		"debug: dispatching command",
+		"info: command handled",
+		"debug: dispatching command",
+		"info: middleware stopped the dispatch",
+		"debug: dispatching command",
+		"info: middleware stopped the dispatch",
+		"warn: command not found",
+	}, messages)
+
+	handled := logger.entries[1].fields
+	assert.Equal(t, "ping", handled["command"])
+	assert.Equal(t, "guild", handled["guild"])
+	assert.Equal(t, "user", handled["user"])
+	assert.Contains(t, handled, "latency")
+	assert.Equal(t, "github.com/FedorLap2006/disgolf_test.stopCommand", logger.entries[3].fields["middleware"])
+	assert.Equal(t, "github.com/FedorLap2006/disgolf_test.stopInvocation", logger.entries[5].fields["middleware"])
+}
+
+func stopCommand(ctx *disgolf.Ctx) {}
+
+func stopInvocation(inv disgolf.Invocation) {}
@@ -0,0 +1,94 @@
+package disgolf
+
+import (
+	"time"
+)
+
+// InteractionResponseDeadline is the time Discord gives to send the initial interaction response.
+const InteractionResponseDeadline = 3 * time.Second
+
+// InvocationKind is the kind of an invocation, as reported to Metrics.
+type InvocationKind string
+
+// Invocation kinds.
+const (
+	InvocationSlash        InvocationKind = "slash"
+	InvocationMessage      InvocationKind = "message"
+	InvocationComponent    InvocationKind = "component"
+	InvocationAutocomplete InvocationKind = "autocomplete"
+)
+
+// InvocationOutcome is the outcome of an invocation, as reported to Metrics.
+type InvocationOutcome string
+
+// Invocation outcomes.
+const (
+	// OutcomeOK means the handlers have returned, and all responses were sent successfully.
+	OutcomeOK InvocationOutcome = "ok"
+	// OutcomeError means sending a response has failed.
+	OutcomeError InvocationOutcome = "error"
+	// OutcomePanic means a handler has panicked.
+	OutcomePanic InvocationOutcome = "panic"
+)
+
+// Metrics records statistics of dispatch and sync.
+// Command is the command path joined with spaces, or the component name for component invocations.
+type Metrics interface {
+	// ObserveInvocation records an invocation, which has finished with the outcome after the duration.
+	ObserveInvocation(kind InvocationKind, command string, duration time.Duration, outcome InvocationOutcome)
+	// ObserveResponseDeadlineMiss records an interaction, which was not responded to within InteractionResponseDeadline.
+	ObserveResponseDeadlineMiss(kind InvocationKind, command string)
+	// ObserveRejection records an invocation rejected by Router.Executor.
+	ObserveRejection(kind InvocationKind, command string)
+	// ObserveSync records a sync of the commands. Guild is empty for global commands.
+	ObserveSync(guild string, duration time.Duration, err error)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveInvocation(InvocationKind, string, time.Duration, InvocationOutcome) {}
+func (nopMetrics) ObserveResponseDeadlineMiss(InvocationKind, string)                         {}
+func (nopMetrics) ObserveRejection(InvocationKind, string)                                    {}
+func (nopMetrics) ObserveSync(string, time.Duration, error)                                   {}
+
+func (r *Router) metrics() Metrics {
+	if r.Metrics == nil {
+		return nopMetrics{}
+	}
+	return r.Metrics
+}
+
+// observe calls the handlers, records the invocation and ends its span. Panics are recorded and propagated.
+func (r *Router) observe(kind InvocationKind, command string, span Span, next func(), failed func() bool) {
+	start := time.Now()
+	outcome := OutcomePanic
+	defer func() {
+		r.metrics().ObserveInvocation(kind, command, time.Since(start), outcome)
+
+		span.SetAttribute(AttributeOutcome, string(outcome))
+		switch outcome {
+		case OutcomeError:
+			span.SetError(errResponseFailed)
+		case OutcomePanic:
+			span.SetError(errHandlerPanic)
+		}
+		span.End()
+	}()
+
+	next()
+	outcome = OutcomeOK
+	if failed() {
+		outcome = OutcomeError
+	}
+}
+
+// observeDeadline records a response deadline miss, if the interaction dispatched at the time
+// was responded to too late, or was not responded to at all after the deadline.
+func (r *Router) observeDeadline(kind InvocationKind, command string, dispatched, responded time.Time) {
+	if responded.IsZero() {
+		responded = time.Now()
+	}
+	if responded.Sub(dispatched) > InteractionResponseDeadline {
+		r.metrics().ObserveResponseDeadlineMiss(kind, command)
+	}
+}
@@ -0,0 +1,112 @@
+package disgolf
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Invocation is a common interface for contexts of all invocation styles (slash commands and message commands).
+// It is implemented by both Ctx and MessageCtx.
+type Invocation interface {
+	// DiscordSession returns the session the command was invoked through.
+	DiscordSession() *discordgo.Session
+	// Command returns the invoked command.
+	Command() *Command
+	// CommandPath returns names of the command and all of its parents, starting from the top-level one.
+	CommandPath() []string
+	// Author returns the user who invoked the command.
+	Author() *discordgo.User
+	// GuildID returns the id of the guild the command was invoked in. It is empty for DMs.
+	GuildID() string
+	// ChannelID returns the id of the channel the command was invoked in.
+	ChannelID() string
+	// Member returns the guild member who invoked the command. It is nil for DMs.
+	Member() *discordgo.Member
+	// Permissions returns the permissions the invoking member has in the channel.
+	Permissions() (int64, error)
+	// BotPermissions returns the permissions the bot has in the channel.
+	BotPermissions() (int64, error)
+	// Translator returns the translator for the locale of the invocation.
+	Translator() *Translator
+	// ReplyText sends a simple (content-only) reply to the invocation.
+	ReplyText(content string) error
+	// Next calls the next middleware / command handler.
+	Next()
+
+	// Context returns the context of the invocation. It is cancelled once the dispatch finishes.
+	Context() context.Context
+	// SetContext replaces the context of the invocation for subsequent middlewares and the handler.
+	SetContext(c context.Context)
+}
+
+// A Middleware processes invocations of any style, before they reach the command handler.
+// Middlewares are attached through Command.CommonMiddlewares and are run for both Handler and MessageHandler chains.
+type Middleware interface {
+	HandleInvocation(inv Invocation)
+}
+
+// MiddlewareFunc is a wrapper around Middleware for functions
+type MiddlewareFunc func(inv Invocation)
+
+// HandleInvocation implements Middleware interface and calls the function with provided invocation
+func (f MiddlewareFunc) HandleInvocation(inv Invocation) { f(inv) }
+
+// HandleCommand implements Handler interface, which allows using the function in Command.Middlewares.
+func (f MiddlewareFunc) HandleCommand(ctx *Ctx) { f(ctx) }
+
+// HandleMessageCommand implements MessageHandler interface, which allows using the function in Command.MessageMiddlewares.
+func (f MiddlewareFunc) HandleMessageCommand(ctx *MessageCtx) { f(ctx) }
+
+// CommandMiddleware adapts a Middleware to the Handler interface.
+func CommandMiddleware(m Middleware) Handler {
+	return commandMiddleware{m}
+}
+
+// MessageCommandMiddleware adapts a Middleware to the MessageHandler interface.
+func MessageCommandMiddleware(m Middleware) MessageHandler {
+	return messageCommandMiddleware{m}
+}
+
+// commandMiddleware is the adapter made by CommandMiddleware. It keeps the middleware, so logs and traces can name it.
+type commandMiddleware struct{ Middleware }
+
+func (m commandMiddleware) HandleCommand(ctx *Ctx) { m.HandleInvocation(ctx) }
+
+// messageCommandMiddleware is the adapter made by MessageCommandMiddleware.
+type messageCommandMiddleware struct{ Middleware }
+
+func (m messageCommandMiddleware) HandleMessageCommand(ctx *MessageCtx) { m.HandleInvocation(ctx) }
+
+// commonMiddlewares returns middlewares of the command, which are applied to all invocation styles.
+func (cmd *Command) commonMiddlewares() []Middleware {
+	middlewares := cmd.CommonMiddlewares
+	middlewares = middlewares[:len(middlewares):len(middlewares)]
+	if cmd.Cooldown != nil {
+		middlewares = append(middlewares, cmd.Cooldown)
+	}
+	if cmd.Concurrency != nil {
+		middlewares = append(middlewares, cmd.Concurrency)
+	}
+	return middlewares
+}
+
+// middlewares returns the full middleware chain of the command for slash command invocations.
+func (cmd *Command) middlewares() []Handler {
+	common := cmd.commonMiddlewares()
+	handlers := make([]Handler, 0, len(common)+len(cmd.Middlewares))
+	for _, m := range common {
+		handlers = append(handlers, CommandMiddleware(m))
+	}
+	return append(handlers, cmd.Middlewares...)
+}
+
+// messageMiddlewares returns the full middleware chain of the command for message command invocations.
+func (cmd *Command) messageMiddlewares() []MessageHandler {
+	common := cmd.commonMiddlewares()
+	handlers := make([]MessageHandler, 0, len(common)+len(cmd.MessageMiddlewares))
+	for _, m := range common {
+		handlers = append(handlers, MessageCommandMiddleware(m))
+	}
+	return append(handlers, cmd.MessageMiddlewares...)
+}
@@ -0,0 +1,73 @@
+package disgolf_test
+
+import (
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommand_CommonMiddlewares(t *testing.T) {
+	var calls []string
+	middleware := disgolf.MiddlewareFunc(func(inv disgolf.Invocation) {
+		calls = append(calls, "common "+inv.Author().ID+" "+inv.GuildID())
+		assert.Equal(t, []string{"parent", "child"}, inv.CommandPath())
+		inv.Next()
+	})
+
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name:        "parent",
+			Description: "parent",
+			SubCommands: disgolf.NewRouter([]*disgolf.Command{
+				{
+					Name:              "child",
+					Description:       "child",
+					CommonMiddlewares: []disgolf.Middleware{middleware},
+					Middlewares: []disgolf.Handler{
+						disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+							calls = append(calls, "slash")
+							ctx.Next()
+						}),
+					},
+					MessageMiddlewares: []disgolf.MessageHandler{
+						disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+							calls = append(calls, "message")
+							ctx.Next()
+						}),
+					},
+					Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+						calls = append(calls, "handler")
+					}),
+					MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+						calls = append(calls, "message handler")
+					}),
+				},
+			}),
+		},
+	})
+
+	r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "guild",
+		Member:  &discordgo.Member{User: &discordgo.User{ID: "user"}},
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: "parent",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "child", Type: discordgo.ApplicationCommandOptionSubCommand},
+			},
+		},
+	}})
+	assert.Equal(t, []string{"common user guild", "slash", "handler"}, calls)
+
+	calls = nil
+	r.MakeMessageHandler(&disgolf.MessageHandlerConfig{Prefixes: []string{"!"}})(nil, &discordgo.MessageCreate{
+		Message: &discordgo.Message{
+			Content: "!parent child",
+			GuildID: "guild",
+			Author:  &discordgo.User{ID: "user"},
+		},
+	})
+	assert.Equal(t, []string{"common user guild", "message", "message handler"}, calls)
+}
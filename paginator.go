@@ -0,0 +1,272 @@
+package disgolf
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultPaginatorTimeout is the time a paginator can be navigated, if its timeout is not specified.
+const DefaultPaginatorTimeout = 5 * time.Minute
+
+// maxSelectOptions is the maximal amount of options in a select menu.
+const maxSelectOptions = 25
+
+// Page is a single page of a paginator.
+type Page struct {
+	Content string
+	Embeds  []*discordgo.MessageEmbed
+}
+
+// A PageSource provides pages for a paginator.
+type PageSource interface {
+	// Len returns the amount of pages.
+	Len() int
+	// Page returns the page by its index, starting from zero.
+	Page(ctx context.Context, index int) (*Page, error)
+}
+
+// StaticPages is a PageSource of pages known in advance.
+type StaticPages []*Page
+
+// Len implements PageSource interface.
+func (p StaticPages) Len() int { return len(p) }
+
+// Page implements PageSource interface.
+func (p StaticPages) Page(_ context.Context, index int) (*Page, error) { return p[index], nil }
+
+// LazyPages is a PageSource, which loads pages on demand (e.g. from a database).
+type LazyPages struct {
+	// Count is the amount of pages.
+	Count int
+	// Load loads the page by its index, starting from zero.
+	Load func(ctx context.Context, index int) (*Page, error)
+}
+
+// Len implements PageSource interface.
+func (p LazyPages) Len() int { return p.Count }
+
+// Page implements PageSource interface.
+func (p LazyPages) Page(ctx context.Context, index int) (*Page, error) { return p.Load(ctx, index) }
+
+// Paginator sends pages as a message with navigation buttons (first, previous, next, last) and a select menu to jump to a page.
+// The interactions with the components are collected by the paginator itself, so no component handlers need to be registered.
+// Once the timeout expires, the components are disabled.
+type Paginator struct {
+	Source PageSource
+	// AuthorOnly restricts the navigation to the author of the invocation. Other users receive an ephemeral notice.
+	AuthorOnly bool
+	// Timeout is the time the paginator can be navigated. Defaults to DefaultPaginatorTimeout.
+	Timeout time.Duration
+	// OnError is called, when a page cannot be loaded, or the message cannot be updated.
+	OnError func(err error)
+}
+
+// Send sends the first page in reply to the invocation, and starts handling the navigation in background.
+// The navigation does not depend on the context of the invocation, it lasts until the timeout expires.
+func (p *Paginator) Send(inv Invocation) error {
+	total := p.Source.Len()
+	if total == 0 {
+		return ErrNoPages
+	}
+	m, err := newInteractiveMessage(inv, "paginator")
+	if err != nil {
+		return err
+	}
+
+	state := &paginatorState{Paginator: p, message: m, total: total, pages: make(map[int]*Page)}
+	page, err := state.page(inv.Context(), 0)
+	if err != nil {
+		return err
+	}
+	if total == 1 {
+		return m.send(state.data(page))
+	}
+
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = DefaultPaginatorTimeout
+	}
+	// NOTE: the context of the invocation is cancelled once the handler returns.
+	// The collector is registered before the message is sent, so no click can reach the component handlers of the router.
+	c, cancel := context.WithTimeout(context.Background(), timeout)
+	collector := m.collect(c, ComponentCollectorConfig{Timeout: timeout})
+	if err = m.send(state.data(page)); err != nil {
+		collector.Stop()
+		cancel()
+		return err
+	}
+	go func() {
+		defer cancel()
+		state.run(c, collector, page)
+	}()
+	return nil
+}
+
+// paginatorState is the state of a sent paginator.
+type paginatorState struct {
+	*Paginator
+	message *interactiveMessage
+	total   int
+	index   int
+	// pages are the loaded pages. Each page is loaded once.
+	pages    map[int]*Page
+	disabled bool
+}
+
+func (s *paginatorState) page(ctx context.Context, index int) (*Page, error) {
+	if page, ok := s.pages[index]; ok {
+		return page, nil
+	}
+	page, err := s.Source.Page(ctx, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load page %d: %w", index, err)
+	}
+	s.pages[index] = page
+	return page, nil
+}
+
+func (s *paginatorState) reportError(err error) {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+}
+
+func (s *paginatorState) run(ctx context.Context, collector *ComponentCollector, page *Page) {
+	for {
+		cctx, err := collector.Next()
+		if err != nil {
+			break
+		}
+		if s.AuthorOnly && s.message.rejectForeignUser(cctx) {
+			continue
+		}
+
+		index, ok := s.target(cctx)
+		if !ok {
+			continue
+		}
+		next, err := s.page(ctx, index)
+		if err != nil {
+			s.reportError(err)
+			_ = cctx.Respond(&discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+			continue
+		}
+		s.index, page = index, next
+		err = cctx.Respond(&discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: s.data(page),
+		})
+		if err != nil {
+			s.reportError(err)
+		}
+	}
+
+	s.disabled = true
+	if err := s.message.edit(s.data(page)); err != nil {
+		s.reportError(err)
+	}
+}
+
+// target returns the index of the page the interaction navigates to.
+func (s *paginatorState) target(ctx *ComponentCtx) (int, bool) {
+	switch s.message.action(ctx) {
+	case "first":
+		return 0, true
+	case "prev":
+		return clamp(s.index-1, 0, s.total-1), true
+	case "next":
+		return clamp(s.index+1, 0, s.total-1), true
+	case "last":
+		return s.total - 1, true
+	case "jump":
+		if len(ctx.Data.Values) == 0 {
+			return 0, false
+		}
+		index, err := strconv.Atoi(ctx.Data.Values[0])
+		if err != nil || index < 0 || index >= s.total {
+			return 0, false
+		}
+		return index, true
+	}
+	return 0, false
+}
+
+func (s *paginatorState) data(page *Page) *discordgo.InteractionResponseData {
+	embeds := page.Embeds
+	if embeds == nil {
+		embeds = []*discordgo.MessageEmbed{}
+	}
+	return &discordgo.InteractionResponseData{
+		Content:    page.Content,
+		Embeds:     embeds,
+		Components: s.components(),
+	}
+}
+
+func (s *paginatorState) components() []discordgo.MessageComponent {
+	if s.total == 1 {
+		return []discordgo.MessageComponent{}
+	}
+
+	first, last := s.index == 0, s.index == s.total-1
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "«", Style: discordgo.SecondaryButton, CustomID: s.message.customID("first"), Disabled: first},
+			discordgo.Button{Label: "‹", Style: discordgo.PrimaryButton, CustomID: s.message.customID("prev"), Disabled: first},
+			discordgo.Button{
+				Label:    fmt.Sprintf("%d/%d", s.index+1, s.total),
+				Style:    discordgo.SecondaryButton,
+				CustomID: s.message.customID("page"),
+				Disabled: true,
+			},
+			discordgo.Button{Label: "›", Style: discordgo.PrimaryButton, CustomID: s.message.customID("next"), Disabled: last},
+			discordgo.Button{Label: "»", Style: discordgo.SecondaryButton, CustomID: s.message.customID("last"), Disabled: last},
+		}},
+	}
+	if s.total > 2 {
+		components = append(components, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    s.message.customID("jump"),
+				Placeholder: "Jump to page",
+				Options:     s.jumpOptions(),
+			},
+		}})
+	}
+	if s.disabled {
+		components = disableComponents(components)
+	}
+	return components
+}
+
+// jumpOptions returns options of the jump menu. If there are too many pages, only the ones around the current page are listed.
+func (s *paginatorState) jumpOptions() []discordgo.SelectMenuOption {
+	start, end := 0, s.total
+	if s.total > maxSelectOptions {
+		start = clamp(s.index-maxSelectOptions/2, 0, s.total-maxSelectOptions)
+		end = start + maxSelectOptions
+	}
+
+	options := make([]discordgo.SelectMenuOption, 0, end-start)
+	for i := start; i < end; i++ {
+		options = append(options, discordgo.SelectMenuOption{
+			Label:   fmt.Sprintf("Page %d", i+1),
+			Value:   strconv.Itoa(i),
+			Default: i == s.index,
+		})
+	}
+	return options
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,167 @@
+package disgolf_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/FedorLap2006/disgolf/disgolftest"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+// messagePayload is a message payload of a captured call. Components are decoded into plain structs, since discordgo cannot decode them in requests.
+type messagePayload struct {
+	Type int `json:"type"`
+	Data struct {
+		Content string                 `json:"content"`
+		Flags   discordgo.MessageFlags `json:"flags"`
+	} `json:"data"`
+	Content    string `json:"content"`
+	Components []struct {
+		Components []struct {
+			CustomID string `json:"custom_id"`
+			Label    string `json:"label"`
+			Disabled bool   `json:"disabled"`
+		} `json:"components"`
+	} `json:"components"`
+}
+
+func decodePayloads(t *testing.T, calls disgolftest.Calls) []messagePayload {
+	payloads := make([]messagePayload, len(calls))
+	for i, call := range calls {
+		assert.NoError(t, call.Decode(&payloads[i]))
+	}
+	return payloads
+}
+
+func TestPaginator(t *testing.T) {
+	var loaded []int
+	var errs []error
+	paginator := &disgolf.Paginator{
+		Source: disgolf.LazyPages{Count: 3, Load: func(ctx context.Context, index int) (*disgolf.Page, error) {
+			loaded = append(loaded, index)
+			if index == 2 {
+				return nil, errors.New("database is down")
+			}
+			return &disgolf.Page{Content: fmt.Sprint("page ", index+1)}, nil
+		}},
+		AuthorOnly: true,
+		Timeout:    100 * time.Millisecond,
+		OnError:    func(err error) { errs = append(errs, err) },
+	}
+	h := disgolftest.New(disgolf.NewRouter([]*disgolf.Command{{
+		Name: "list",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			assert.NoError(t, paginator.Send(ctx))
+		}),
+	}}))
+
+	i := disgolftest.CommandInteraction("list")
+	i.ID = "100"
+	calls := h.Dispatch(i)
+	if responses := decodePayloads(t, calls.Filter(http.MethodPost, "interactions/*/*/callback")); assert.Len(t, responses, 1) {
+		assert.Equal(t, "page 1", responses[0].Data.Content)
+	}
+
+	await := func(n int) []messagePayload {
+		var calls disgolftest.Calls
+		assert.Eventually(t, func() bool {
+			calls = h.Transport.Calls().Filter(http.MethodPost, "interactions/*/*/callback")
+			return len(calls) >= n
+		}, time.Second, time.Millisecond)
+		return decodePayloads(t, calls)
+	}
+
+	h.Dispatch(disgolftest.ComponentInteraction("paginator:100:next"))
+	assert.Equal(t, "page 2", await(2)[1].Data.Content)
+
+	foreign := disgolftest.ComponentInteraction("paginator:100:first")
+	foreign.Member = &discordgo.Member{User: &discordgo.User{ID: "5"}}
+	foreign.GuildID = h.GuildID
+	h.Dispatch(foreign)
+	if response := await(3)[2]; assert.Equal(t, discordgo.MessageFlagsEphemeral, response.Data.Flags) {
+		assert.Equal(t, disgolf.ErrAuthorOnly.Error(), response.Data.Content)
+	}
+
+	h.Dispatch(disgolftest.ComponentInteraction("paginator:100:jump", "2"))
+	assert.Equal(t, int(discordgo.InteractionResponseDeferredMessageUpdate), await(4)[3].Type)
+
+	var edits disgolftest.Calls
+	assert.Eventually(t, func() bool {
+		edits = h.Transport.Calls().Filter(http.MethodPatch, "webhooks/*/*/messages/@original")
+		return len(edits) == 1
+	}, time.Second, time.Millisecond)
+	if edit := decodePayloads(t, edits)[0]; assert.Len(t, edit.Components, 2) {
+		assert.Equal(t, "page 2", edit.Content)
+		assert.Equal(t, "2/3", edit.Components[0].Components[2].Label)
+		for _, row := range edit.Components {
+			for _, component := range row.Components {
+				assert.True(t, component.Disabled, component.CustomID)
+			}
+		}
+	}
+
+	assert.Equal(t, []int{0, 1, 2}, loaded)
+	if assert.Len(t, errs, 1) {
+		assert.True(t, strings.HasSuffix(errs[0].Error(), "database is down"))
+	}
+}
+
+func TestPaginator_MessageCommand(t *testing.T) {
+	paginator := &disgolf.Paginator{Source: disgolf.StaticPages{{Content: "only"}}}
+	h := disgolftest.New(disgolf.NewRouter([]*disgolf.Command{{
+		Name: "list",
+		MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+			assert.NoError(t, paginator.Send(ctx))
+		}),
+	}}))
+
+	messages := decodePayloads(t, h.Send("!list").Filter(http.MethodPost, "channels/*/messages"))
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, "only", messages[0].Content)
+		assert.Empty(t, messages[0].Components)
+	}
+
+	assert.Equal(t, disgolf.ErrNoPages, (&disgolf.Paginator{Source: disgolf.StaticPages{}}).Send(&disgolf.MessageCtx{}))
+}
+
+func TestPaginator_Concurrency(t *testing.T) {
+	loadErrs := make(chan error, 2)
+	paginator := &disgolf.Paginator{
+		Source: disgolf.LazyPages{Count: 2, Load: func(ctx context.Context, index int) (*disgolf.Page, error) {
+			loadErrs <- ctx.Err()
+			return &disgolf.Page{Content: fmt.Sprint("page ", index+1)}, nil
+		}},
+		Timeout: time.Second,
+	}
+	h := disgolftest.New(disgolf.NewRouter([]*disgolf.Command{{
+		Name:        "list",
+		Concurrency: &disgolf.ConcurrencyLimit{},
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			assert.NoError(t, paginator.Send(ctx))
+		}),
+	}}))
+
+	i := disgolftest.CommandInteraction("list")
+	i.ID = "100"
+	h.Dispatch(i)
+	h.Dispatch(disgolftest.ComponentInteraction("paginator:100:next"))
+
+	var calls disgolftest.Calls
+	assert.Eventually(t, func() bool {
+		calls = h.Transport.Calls().Filter(http.MethodPost, "interactions/*/*/callback")
+		return len(calls) == 2
+	}, time.Second, time.Millisecond)
+	if responses := decodePayloads(t, calls); assert.Len(t, responses, 2) {
+		assert.Equal(t, "page 2", responses[1].Data.Content)
+	}
+	assert.NoError(t, <-loadErrs)
+	assert.NoError(t, <-loadErrs)
+	assert.Empty(t, h.Transport.Calls().Filter(http.MethodPatch, "webhooks/*/*/messages/@original"))
+}
@@ -0,0 +1,266 @@
+package disgolf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDurationBuckets are the default upper bounds (in seconds) of duration histogram buckets.
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(buckets []float64, value float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(buckets))
+	}
+	for i, bound := range buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+type invocationKey struct {
+	kind    InvocationKind
+	command string
+}
+
+type invocationStats struct {
+	total, errors, panics, deadlineMisses, rejections uint64
+	duration                                          histogram
+}
+
+type syncStats struct {
+	errors   uint64
+	duration histogram
+}
+
+// PrometheusMetrics is an in-memory implementation of Metrics, exposing the statistics in Prometheus text format.
+// It serves as an http.Handler, which can be mounted as a scrape endpoint (e.g. /metrics).
+//
+// Exposed metrics are:
+//
+//	disgolf_invocations_total{kind,command}
+//	disgolf_invocation_errors_total{kind,command}
+//	disgolf_invocation_panics_total{kind,command}
+//	disgolf_invocation_duration_seconds{kind,command} (histogram)
+//	disgolf_response_deadline_misses_total{kind,command}
+//	disgolf_invocation_rejections_total{kind,command}
+//	disgolf_sync_errors_total{scope}
+//	disgolf_sync_duration_seconds{scope} (histogram)
+//
+// Scope is either "global" or "guild".
+type PrometheusMetrics struct {
+	// Buckets are the upper bounds of duration histogram buckets. Defaults to DefaultDurationBuckets.
+	// It must not be changed after the first observation.
+	Buckets []float64
+
+	mtx         sync.Mutex
+	invocations map[invocationKey]*invocationStats
+	syncs       map[string]*syncStats
+}
+
+// NewPrometheusMetrics constructs empty metrics.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		invocations: make(map[invocationKey]*invocationStats),
+		syncs:       make(map[string]*syncStats),
+	}
+}
+
+func (m *PrometheusMetrics) buckets() []float64 {
+	if m.Buckets == nil {
+		return DefaultDurationBuckets
+	}
+	return m.Buckets
+}
+
+func (m *PrometheusMetrics) invocation(kind InvocationKind, command string) *invocationStats {
+	key := invocationKey{kind, command}
+	stats, ok := m.invocations[key]
+	if !ok {
+		stats = &invocationStats{}
+		m.invocations[key] = stats
+	}
+	return stats
+}
+
+// ObserveInvocation implements Metrics interface.
+func (m *PrometheusMetrics) ObserveInvocation(kind InvocationKind, command string, duration time.Duration, outcome InvocationOutcome) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	stats := m.invocation(kind, command)
+	stats.total++
+	switch outcome {
+	case OutcomeError:
+		stats.errors++
+	case OutcomePanic:
+		stats.panics++
+	}
+	stats.duration.observe(m.buckets(), duration.Seconds())
+}
+
+// ObserveResponseDeadlineMiss implements Metrics interface.
+func (m *PrometheusMetrics) ObserveResponseDeadlineMiss(kind InvocationKind, command string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.invocation(kind, command).deadlineMisses++
+}
+
+// ObserveRejection implements Metrics interface.
+func (m *PrometheusMetrics) ObserveRejection(kind InvocationKind, command string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.invocation(kind, command).rejections++
+}
+
+// ObserveSync implements Metrics interface.
+func (m *PrometheusMetrics) ObserveSync(guild string, duration time.Duration, err error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	scope := "global"
+	if guild != "" {
+		scope = "guild"
+	}
+	stats, ok := m.syncs[scope]
+	if !ok {
+		stats = &syncStats{}
+		m.syncs[scope] = stats
+	}
+	if err != nil {
+		stats.errors++
+	}
+	stats.duration.observe(m.buckets(), duration.Seconds())
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels formats label pairs (alternating names and values).
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i != 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i] + `="` + labelValueReplacer.Replace(pairs[i+1]) + `"`)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func writeHistogram(w io.Writer, name string, buckets []float64, h *histogram, pairs ...string) {
+	for i, bound := range buckets {
+		var count uint64
+		if h.counts != nil {
+			count = h.counts[i]
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, labels(append(pairs, "le", formatFloat(bound))...), count)
+	}
+	fmt.Fprintf(w, "%s_bucket%s %d\n", name, labels(append(pairs, "le", "+Inf")...), h.count)
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, labels(pairs...), formatFloat(h.sum))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels(pairs...), h.count)
+}
+
+// WriteTo writes the metrics in Prometheus text exposition format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	buckets := m.buckets()
+
+	keys := make([]invocationKey, 0, len(m.invocations))
+	for key := range m.invocations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		return keys[i].command < keys[j].command
+	})
+
+	counters := []struct {
+		name, help string
+		value      func(*invocationStats) uint64
+	}{
+		{"disgolf_invocations_total", "Total number of invocations.", func(s *invocationStats) uint64 { return s.total }},
+		{"disgolf_invocation_errors_total", "Total number of invocations, which have failed to respond.", func(s *invocationStats) uint64 { return s.errors }},
+		{"disgolf_invocation_panics_total", "Total number of invocations, which have panicked.", func(s *invocationStats) uint64 { return s.panics }},
+		{"disgolf_response_deadline_misses_total", "Total number of interactions, which were not responded to in time.", func(s *invocationStats) uint64 { return s.deadlineMisses }},
+		{"disgolf_invocation_rejections_total", "Total number of invocations rejected by the executor.", func(s *invocationStats) uint64 { return s.rejections }},
+	}
+	for _, counter := range counters {
+		writeHeader(bw, counter.name, "counter", counter.help)
+		for _, key := range keys {
+			fmt.Fprintf(bw, "%s%s %d\n", counter.name, labels("kind", string(key.kind), "command", key.command), counter.value(m.invocations[key]))
+		}
+	}
+	writeHeader(bw, "disgolf_invocation_duration_seconds", "histogram", "Duration of invocation handlers.")
+	for _, key := range keys {
+		writeHistogram(bw, "disgolf_invocation_duration_seconds", buckets, &m.invocations[key].duration, "kind", string(key.kind), "command", key.command)
+	}
+
+	scopes := make([]string, 0, len(m.syncs))
+	for scope := range m.syncs {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	writeHeader(bw, "disgolf_sync_errors_total", "counter", "Total number of failed command syncs.")
+	for _, scope := range scopes {
+		fmt.Fprintf(bw, "disgolf_sync_errors_total%s %d\n", labels("scope", scope), m.syncs[scope].errors)
+	}
+	writeHeader(bw, "disgolf_sync_duration_seconds", "histogram", "Duration of command syncs.")
+	for _, scope := range scopes {
+		writeHistogram(bw, "disgolf_sync_duration_seconds", buckets, &m.syncs[scope].duration, "scope", scope)
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP implements http.Handler interface.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
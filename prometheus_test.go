@@ -0,0 +1,65 @@
+package disgolf_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := disgolf.NewPrometheusMetrics()
+	metrics.Buckets = []float64{0.1, 1}
+
+	r := disgolf.NewRouter([]*disgolf.Command{
+		{
+			Name:    "ping",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {}),
+		},
+		{
+			Name:    "crash",
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { panic("crash") }),
+		},
+	})
+	r.Metrics = metrics
+
+	dispatch := func(name string) {
+		r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{Name: name},
+		}})
+	}
+	dispatch("ping")
+	dispatch("ping")
+	assert.Panics(t, func() { dispatch("crash") })
+	metrics.ObserveInvocation(disgolf.InvocationMessage, `say "hi"`, 500*time.Millisecond, disgolf.OutcomeError)
+	metrics.ObserveResponseDeadlineMiss(disgolf.InvocationSlash, "ping")
+	metrics.ObserveSync("", 2*time.Second, errors.New("rate limited"))
+
+	var b strings.Builder
+	_, err := metrics.WriteTo(&b)
+	assert.NoError(t, err)
+	out := b.String()
+
+	for _, line := range []string{
+		"# TYPE disgolf_invocations_total counter",
+		`disgolf_invocations_total{kind="slash",command="ping"} 2`,
+		`disgolf_invocations_total{kind="slash",command="crash"} 1`,
+		`disgolf_invocation_panics_total{kind="slash",command="crash"} 1`,
+		`disgolf_invocation_errors_total{kind="message",command="say \"hi\""} 1`,
+		`disgolf_invocation_duration_seconds_bucket{kind="message",command="say \"hi\"",le="0.1"} 0`,
+		`disgolf_invocation_duration_seconds_bucket{kind="message",command="say \"hi\"",le="1"} 1`,
+		`disgolf_invocation_duration_seconds_bucket{kind="message",command="say \"hi\"",le="+Inf"} 1`,
+		`disgolf_invocation_duration_seconds_sum{kind="message",command="say \"hi\""} 0.5`,
+		`disgolf_response_deadline_misses_total{kind="slash",command="ping"} 1`,
+		`disgolf_sync_errors_total{scope="global"} 1`,
+		`disgolf_sync_duration_seconds_bucket{scope="global",le="+Inf"} 1`,
+		`disgolf_sync_duration_seconds_count{scope="global"} 1`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+}
@@ -0,0 +1,170 @@
+package disgolf
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Record is a single event recorded by Recorder. Exactly one of Interaction and Message is set.
+type Record struct {
+	Time        time.Time              `json:"time"`
+	Interaction *discordgo.Interaction `json:"interaction,omitempty"`
+	Message     *discordgo.Message     `json:"message,omitempty"`
+}
+
+// Recorder is a middleware, which writes invocations (interactions and messages) to a JSONL stream,
+// one record per line. Records are copied and redacted before being written, the invocation itself is left intact.
+//
+// Recordings can be replayed with disgolftest.Harness.Replay.
+type Recorder struct {
+	// Redact is applied to each record before it is written. Defaults to RedactRecord.
+	Redact func(record *Record)
+
+	mtx sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NewRecorder constructs a recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// HandleInvocation implements Middleware interface.
+func (rec *Recorder) HandleInvocation(inv Invocation) {
+	switch ctx := inv.(type) {
+	case *Ctx:
+		rec.Record(&Record{Interaction: ctx.Interaction})
+	case *MessageCtx:
+		// NOTE: the prefix is trimmed from the content by the message handler, so it is restored for the replay.
+		message := *ctx.Message
+		message.Content = ctx.Prefix + message.Content
+		rec.Record(&Record{Message: &message})
+	}
+	inv.Next()
+}
+
+// HandleCommand implements Handler interface, which allows using the recorder in Command.Middlewares.
+func (rec *Recorder) HandleCommand(ctx *Ctx) { rec.HandleInvocation(ctx) }
+
+// HandleMessageCommand implements MessageHandler interface, which allows using the recorder in Command.MessageMiddlewares.
+func (rec *Recorder) HandleMessageCommand(ctx *MessageCtx) { rec.HandleInvocation(ctx) }
+
+// Record writes the record. Write errors are retained and reported by Err.
+func (rec *Recorder) Record(record *Record) {
+	if record.Time.IsZero() {
+		record.Time = time.Now().UTC()
+	}
+
+	// NOTE: the record is copied through JSON, so redaction does not affect the live objects.
+	data, err := json.Marshal(record)
+	if err != nil {
+		rec.setErr(err)
+		return
+	}
+	var copied Record
+	if err = json.Unmarshal(data, &copied); err != nil {
+		rec.setErr(err)
+		return
+	}
+
+	redact := rec.Redact
+	if redact == nil {
+		redact = RedactRecord
+	}
+	redact(&copied)
+
+	if data, err = json.Marshal(&copied); err != nil {
+		rec.setErr(err)
+		return
+	}
+
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+	if _, err = rec.w.Write(append(data, '\n')); err != nil {
+		rec.err = err
+	}
+}
+
+func (rec *Recorder) setErr(err error) {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+	rec.err = err
+}
+
+// Err returns the last error occurred while recording.
+func (rec *Recorder) Err() error {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+	return rec.err
+}
+
+// RedactRecord removes tokens and personal information from the record.
+// Ids are kept, since routing and permission checks depend on them, usernames are replaced with ones derived from ids.
+func RedactRecord(record *Record) {
+	if i := record.Interaction; i != nil {
+		i.Token = ""
+		redactUser(i.User)
+		redactMember(i.Member)
+		redactMessage(i.Message)
+		if data, ok := i.Data.(discordgo.ApplicationCommandInteractionData); ok && data.Resolved != nil {
+			for _, user := range data.Resolved.Users {
+				redactUser(user)
+			}
+			for _, member := range data.Resolved.Members {
+				redactMember(member)
+			}
+			for _, message := range data.Resolved.Messages {
+				redactMessage(message)
+			}
+			for _, attachment := range data.Resolved.Attachments {
+				attachment.URL, attachment.ProxyURL = "", ""
+			}
+		}
+	}
+	redactMessage(record.Message)
+}
+
+func redactUser(user *discordgo.User) {
+	if user == nil {
+		return
+	}
+	*user = discordgo.User{
+		ID:            user.ID,
+		Username:      "user" + user.ID,
+		Discriminator: "0000",
+		Bot:           user.Bot,
+		System:        user.System,
+	}
+}
+
+func redactMember(member *discordgo.Member) {
+	if member == nil {
+		return
+	}
+	member.Nick, member.Avatar = "", ""
+	redactUser(member.User)
+}
+
+func redactMessage(message *discordgo.Message) {
+	if message == nil {
+		return
+	}
+	redactUser(message.Author)
+	redactMember(message.Member)
+	for _, user := range message.Mentions {
+		redactUser(user)
+	}
+	for _, attachment := range message.Attachments {
+		attachment.URL, attachment.ProxyURL = "", ""
+	}
+	if message.Interaction != nil {
+		redactUser(message.Interaction.User)
+		redactMember(message.Interaction.Member)
+	}
+	redactMessage(message.ReferencedMessage)
+}
@@ -1,7 +1,11 @@
 package disgolf
 
 import (
+	"context"
+	"encoding/json"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -14,16 +18,57 @@ type Router struct {
 	// NOTE: it is not recommended to use it directly, use Register, Get, Update, Unregister functions instead.
 	Commands map[string]*Command
 
+	// Components is a map of registered component handlers.
+	// Key is the component name, which is the part of the custom id before the first colon (see ComponentCustomID).
+	//
+	// NOTE: it is not recommended to use it directly, use RegisterComponent and UnregisterComponent functions instead.
+	Components map[string]ComponentHandler
+
 	Syncer CommandSyncer
+	// Logger receives events of dispatch and sync. If nil, nothing is logged.
+	Logger Logger
+	// Metrics records statistics of dispatch and sync. If nil, nothing is recorded.
+	Metrics Metrics
+	// Executor runs the handlers of invocations, e.g. WorkerPool. If nil, handlers are run by the caller of the router.
+	Executor Executor
+	// Tracer traces invocations. Each invocation is a span with child spans for its middlewares and the handler.
+	// The span is propagated through the context of the invocation (see Invocation.Context). If nil, nothing is traced.
+	Tracer Tracer
+	// CheckFailureResponse is called when an invocation fails a check (see Check). If nil, DefaultCheckFailureResponse is used.
+	CheckFailureResponse func(inv Invocation, err error)
+	// I18n configures translations of the responses. If nil, translators of the contexts return message keys as they are.
+	I18n *I18n
+
+	// order is the registration order of the commands.
+	order               []string
+	componentCollectors collectorHub
+	messageCollectors   collectorHub
 }
 
 // Register registers the command.
 func (r *Router) Register(cmd *Command) {
 	if _, ok := r.Commands[cmd.Name]; !ok {
 		r.Commands[cmd.Name] = cmd
+		r.order = append(r.order, cmd.Name)
 	}
 }
 
+// RegisterComponent registers the component handler under the name.
+func (r *Router) RegisterComponent(name string, handler ComponentHandler) {
+	r.Components[name] = handler
+}
+
+// UnregisterComponent removes the component handler from router
+func (r *Router) UnregisterComponent(name string) (handler ComponentHandler, existed bool) {
+	handler, existed = r.Components[name]
+
+	if existed {
+		delete(r.Components, name)
+	}
+
+	return
+}
+
 // Get returns a command by specified name.
 func (r *Router) Get(name string) *Command {
 	if r == nil {
@@ -49,21 +94,40 @@ func (r *Router) Unregister(name string) (command *Command, existed bool) {
 
 	if existed {
 		delete(r.Commands, name)
+		for i, n := range r.order {
+			if n == name {
+				r.order = append(r.order[:i], r.order[i+1:]...)
+				break
+			}
+		}
 	}
 
 	return
 }
 
-// List returns all registered commands
+// List returns all registered commands in the order of registration.
+// Commands added to Router.Commands directly come last, sorted by name.
 func (r *Router) List() (list []*Command) {
 	if r == nil {
 		return nil
 	}
 
-	for _, c := range r.Commands {
-		list = append(list, c)
+	listed := make(map[string]bool, len(r.order))
+	for _, name := range r.order {
+		if c, ok := r.Commands[name]; ok && !listed[name] {
+			list = append(list, c)
+			listed[name] = true
+		}
 	}
-	return
+
+	var rest []*Command
+	for name, c := range r.Commands {
+		if !listed[name] {
+			rest = append(rest, c)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool { return rest[i].Name < rest[j].Name })
+	return append(list, rest...)
 }
 
 // Count returns amount of commands stored
@@ -74,6 +138,16 @@ func (r *Router) Count() (c int) {
 	return len(r.Commands)
 }
 
+// Validate checks all the commands with ValidateCommand. It returns the first error found.
+func (r *Router) Validate() error {
+	for _, cmd := range r.List() {
+		if err := ValidateCommand(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // A CommandSyncer syncs all the commands with Discord.
 type CommandSyncer interface {
 	Sync(r *Router, s *discordgo.Session, application, guild string) error
@@ -88,23 +162,55 @@ func (BulkCommandSyncer) Sync(r *Router, s *discordgo.Session, application strin
 		panic("empty application id")
 	}
 
-	var commands []*discordgo.ApplicationCommand
-	for _, c := range r.Commands {
-		commands = append(commands, c.ApplicationCommand())
+	commands := r.applicationCommandPayloads()
+
+	// NOTE: ApplicationCommandBulkOverwrite is not used, because discordgo.ApplicationCommand lacks some of the fields.
+	endpoint := discordgo.EndpointApplicationGlobalCommands(application)
+	if guild != "" {
+		endpoint = discordgo.EndpointApplicationGuildCommands(application, guild)
 	}
-	_, err := s.ApplicationCommandBulkOverwrite(application, guild, commands)
+	_, err := s.RequestWithBucketID("PUT", endpoint, commands, endpoint)
 	return err
 }
 
+func (r *Router) applicationCommandPayloads() []*applicationCommandPayload {
+	commands := []*applicationCommandPayload{}
+	for _, c := range r.List() {
+		commands = append(commands, c.applicationCommandPayload())
+	}
+	return commands
+}
+
+// ExportSchema returns the commands as the JSON sent to Discord by BulkCommandSyncer, indented for readability.
+// Commands and subcommands are listed in the order of registration, so the output is stable and can be compared against a golden file.
+func (r *Router) ExportSchema() ([]byte, error) {
+	return json.MarshalIndent(r.applicationCommandPayloads(), "", "  ")
+}
+
 // Sync wraps Router.Syncer and automatically detects application id.
+// Commands are validated before syncing (see Validate).
 func (r *Router) Sync(s *discordgo.Session, application, guild string) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
 	if application == "" {
-		if s.State.User == nil {
-			panic("cannot determine application id")
+		if s.State == nil || s.State.User == nil {
+			return ErrUnknownApplication
 		}
 		application = s.State.User.ID
 	}
-	return r.Syncer.Sync(r, s, application, guild)
+
+	l := r.logger()
+	start := time.Now()
+	l.Info("syncing commands", "application", application, "guild", guild, "commands", r.Count())
+	err := r.Syncer.Sync(r, s, application, guild)
+	r.metrics().ObserveSync(guild, time.Since(start), err)
+	if err != nil {
+		l.Error("failed to sync commands", "application", application, "guild", guild, "error", err, "latency", time.Since(start))
+		return err
+	}
+	l.Info("commands synced", "application", application, "guild", guild, "latency", time.Since(start))
+	return nil
 }
 
 func (r *Router) getSubcommand(cmd *Command, opt *discordgo.ApplicationCommandInteractionDataOption, parent []Handler) (*Command, *discordgo.ApplicationCommandInteractionDataOption, []Handler) {
@@ -115,9 +221,15 @@ func (r *Router) getSubcommand(cmd *Command, opt *discordgo.ApplicationCommandIn
 	subcommand := cmd.SubCommands.Get(opt.Name)
 	switch opt.Type {
 	case discordgo.ApplicationCommandOptionSubCommand:
-		return subcommand, opt, append(parent, append(subcommand.Middlewares, subcommand.Handler)...)
+		if subcommand == nil {
+			return nil, nil, nil
+		}
+		return subcommand, opt, append(append(parent, subcommand.middlewares()...), subcommand.Handler)
 	case discordgo.ApplicationCommandOptionSubCommandGroup:
-		return r.getSubcommand(subcommand, opt.Options[0], append(parent, subcommand.Middlewares...))
+		if subcommand == nil {
+			return nil, nil, nil
+		}
+		return r.getSubcommand(subcommand, opt.Options[0], append(parent, subcommand.middlewares()...))
 	}
 
 	return cmd, nil, append(parent, cmd.Handler)
@@ -125,25 +237,126 @@ func (r *Router) getSubcommand(cmd *Command, opt *discordgo.ApplicationCommandIn
 
 // HandleInteraction is an interaction handler passed to discordgo.Session.AddHandler.
 func (r *Router) HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i.Type != discordgo.InteractionApplicationCommand {
-		return
+	r.handleInteraction(context.Background(), s, i.Interaction, nil)
+}
+
+// handleInteraction routes the interaction. If respond is not nil, it is used to send the initial response.
+// Spans of the invocation are started under the base context (e.g. the context of the HTTP request).
+// It returns false, if no handler has received the interaction (e.g. the command does not exist, or the invocation was rejected).
+func (r *Router) handleInteraction(base context.Context, s *discordgo.Session, i *discordgo.Interaction, respond responder) bool {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		return r.handleCommand(base, s, i, respond)
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		return r.handleAutocomplete(base, s, i, respond)
+	case discordgo.InteractionMessageComponent:
+		return r.handleComponent(base, s, i, respond)
 	}
+	return false
+}
+
+func (r *Router) handleCommand(base context.Context, s *discordgo.Session, i *discordgo.Interaction, respond responder) bool {
 	data := i.ApplicationCommandData()
 
+	l := r.logger()
 	cmd := r.Get(data.Name)
 	if cmd == nil {
-		return
+		l.Warn("command not found", interactionFields(i, "command", data.Name)...)
+		return false
 	}
 	var parent *discordgo.ApplicationCommandInteractionDataOption
-	handlers := append(cmd.Middlewares, cmd.Handler)
+	handlers := append(cmd.middlewares(), cmd.Handler)
 	if len(data.Options) != 0 {
-		cmd, parent, handlers = r.getSubcommand(cmd, data.Options[0], cmd.Middlewares)
+		cmd, parent, handlers = r.getSubcommand(cmd, data.Options[0], cmd.middlewares())
 	}
 
-	if cmd != nil {
-		ctx := NewCtx(s, cmd, i.Interaction, parent, handlers)
-		ctx.Next()
+	if cmd == nil {
+		l.Warn("subcommand not found", interactionFields(i, "command", data.Name)...)
+		return false
 	}
+
+	ctx := NewCtx(s, cmd, i, parent, handlers)
+	ctx.respond = respond
+	ctx.translator = r.I18n.translator(i.Locale, i.GuildID, i.GuildLocale)
+	ctx.router = r
+
+	path := ctx.CommandPath()
+	command := strings.Join(path, " ")
+	l.Debug("dispatching command", interactionFields(i, "command", command)...)
+	start := time.Now()
+	return r.execute(InvocationSlash, command, i.GuildID, interactionFields(i), func() {
+		c, span := r.startSpan(base, InvocationSlash, command, interactionFields(i))
+		c, cancel := invocationContext(c)
+		defer cancel()
+		ctx.context = c
+		ctx.remainingHandlers = r.traceHandlers(handlers)
+		r.observe(InvocationSlash, command, span, ctx.Next, func() bool { return ctx.failed })
+		r.observeDeadline(InvocationSlash, command, start, ctx.respondedAt)
+
+		var stopped interface{}
+		if n := len(ctx.remainingHandlers); n != 0 {
+			stopped = handlers[len(handlers)-n-1]
+		}
+		logDispatch(l, path, start, stopped, interactionFields(i))
+	})
+}
+
+func (r *Router) handleAutocomplete(base context.Context, s *discordgo.Session, i *discordgo.Interaction, respond responder) bool {
+	data := i.ApplicationCommandData()
+
+	cmd := r.Get(data.Name)
+	if cmd == nil {
+		r.logger().Warn("command not found", interactionFields(i, "command", data.Name)...)
+		return false
+	}
+	var parent *discordgo.ApplicationCommandInteractionDataOption
+	if len(data.Options) != 0 {
+		cmd, parent, _ = r.getSubcommand(cmd, data.Options[0], nil)
+	}
+
+	if cmd != nil && cmd.AutocompleteHandler != nil {
+		ctx := NewCtx(s, cmd, i, parent, []Handler{cmd.AutocompleteHandler})
+		ctx.respond = respond
+		ctx.translator = r.I18n.translator(i.Locale, i.GuildID, i.GuildLocale)
+
+		command := strings.Join(ctx.CommandPath(), " ")
+		r.logger().Debug("dispatching autocomplete", interactionFields(i, "command", command)...)
+		start := time.Now()
+		return r.execute(InvocationAutocomplete, command, i.GuildID, interactionFields(i), func() {
+			c, span := r.startSpan(base, InvocationAutocomplete, command, interactionFields(i))
+			c, cancel := invocationContext(c)
+			defer cancel()
+			ctx.context = c
+			ctx.remainingHandlers = r.traceHandlers(ctx.remainingHandlers)
+			r.observe(InvocationAutocomplete, command, span, ctx.Next, func() bool { return ctx.failed })
+			r.observeDeadline(InvocationAutocomplete, command, start, ctx.respondedAt)
+		})
+	}
+	return false
+}
+
+func (r *Router) handleComponent(base context.Context, s *discordgo.Session, i *discordgo.Interaction, respond responder) bool {
+	ctx := NewComponentCtx(s, i)
+	ctx.respond = respond
+
+	name, _ := splitCustomID(ctx.Data.CustomID)
+	if r.componentCollectors.dispatch(ctx) {
+		r.logger().Debug("component collected", interactionFields(i, "component", name)...)
+		return true
+	}
+	handler, ok := r.Components[name]
+	if !ok {
+		r.logger().Warn("component handler not found", interactionFields(i, "component", name)...)
+		return false
+	}
+	r.logger().Debug("dispatching component", interactionFields(i, "component", name)...)
+
+	start := time.Now()
+	return r.execute(InvocationComponent, name, i.GuildID, interactionFields(i), func() {
+		_, span := r.startSpan(base, InvocationComponent, name, interactionFields(i))
+		r.observe(InvocationComponent, name, span, func() { handler.HandleComponent(ctx) }, func() bool { return ctx.failed })
+		r.observeDeadline(InvocationComponent, name, start, ctx.respondedAt)
+	})
 }
 
 type MessageHandlerConfig struct {
@@ -154,27 +367,36 @@ type MessageHandlerConfig struct {
 	ArgumentDelimiter string
 }
 
-func (r *Router) getMessageSubcommand(cmd *Command, arguments []string, parent []MessageHandler) (*Command, []string, []MessageHandler) {
+func (r *Router) getMessageSubcommand(cmd *Command, arguments []string, path []string, parent []MessageHandler) (*Command, []string, []string, []MessageHandler) {
 	if len(arguments) == 0 {
-		return cmd, arguments, append(parent, cmd.MessageHandler)
+		return cmd, arguments, path, append(parent, cmd.MessageHandler)
 	}
 	subcommand := cmd.SubCommands.Get(arguments[0])
 	if subcommand != nil {
+		path = append(path, subcommand.Name)
 		if len(arguments) > 1 {
-			return r.getMessageSubcommand(subcommand, arguments[1:], append(parent, subcommand.MessageMiddlewares...)) // TODO: opt-out
+			return r.getMessageSubcommand(subcommand, arguments[1:], path, append(parent, subcommand.messageMiddlewares()...)) // TODO: opt-out
 		} else {
-			return subcommand, arguments[1:], append(parent, append(subcommand.MessageMiddlewares, subcommand.MessageHandler)...)
+			return subcommand, arguments[1:], path, append(append(parent, subcommand.messageMiddlewares()...), subcommand.MessageHandler)
 		}
 	}
-	return cmd, arguments, append(parent, cmd.MessageHandler)
+	return cmd, arguments, path, append(parent, cmd.MessageHandler)
 }
 
+// MakeMessageHandler makes a message handler, which routes message commands.
+// Messages accepted by message collectors (see MessageCtx.CollectMessages) are not routed.
 func (r *Router) MakeMessageHandler(cfg *MessageHandlerConfig) func(s *discordgo.Session, m *discordgo.MessageCreate) {
 	if cfg.ArgumentDelimiter == "" {
 		cfg.ArgumentDelimiter = " "
 	}
 	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if r.messageCollectors.dispatch(m.Message) {
+			r.logger().Debug("message collected", messageFields(m.Message)...)
+			return
+		}
+
 		var match bool
+		var matched string
 		var prefixes []string
 		prefixes = cfg.Prefixes
 		if cfg.MentionPrefix {
@@ -188,12 +410,15 @@ func (r *Router) MakeMessageHandler(cfg *MessageHandlerConfig) func(s *discordgo
 		for _, prefix := range prefixes {
 			if strings.HasPrefix(m.Content, prefix) {
 				match = true
+				matched = prefix
 				m.Content = strings.TrimSpace(strings.TrimPrefix(m.Content, prefix))
 				break
 			}
 		}
 
+		l := r.logger()
 		if !match {
+			l.Debug("message does not match any prefix", messageFields(m.Message)...)
 			return
 		}
 
@@ -203,23 +428,62 @@ func (r *Router) MakeMessageHandler(cfg *MessageHandlerConfig) func(s *discordgo
 
 		command, ok := r.Commands[commandName]
 		if !ok {
+			l.Debug("command not found", messageFields(m.Message, "command", commandName)...)
 			return
 		}
 		arguments = arguments[1:]
 
-		command, arguments, handlers := r.getMessageSubcommand(command, arguments, command.MessageMiddlewares)
+		// NOTE: unlike slash commands, access rules of message commands are not enforced by Discord.
+		handlers := command.messageMiddlewares()
+		if check := command.accessCheck(); check != nil {
+			handlers = append([]MessageHandler{check}, handlers...)
+		}
+
+		command, arguments, path, handlers := r.getMessageSubcommand(command, arguments, []string{command.Name}, handlers)
 		if command.MessageHandler == nil {
+			l.Debug("command has no message handler", messageFields(m.Message, "command", strings.Join(path, " "))...)
 			return
 		}
 
 		ctx := NewMessageCtx(s, command, m.Message, arguments, handlers)
-		ctx.Next()
+		ctx.Prefix = matched
+		ctx.path = path
+		ctx.translator = r.I18n.translator("", m.GuildID, nil)
+		ctx.router = r
+
+		name := strings.Join(path, " ")
+		l.Debug("dispatching message command", messageFields(m.Message, "command", name)...)
+		start := time.Now()
+		r.execute(InvocationMessage, name, m.GuildID, messageFields(m.Message), func() {
+			c, span := r.startSpan(context.Background(), InvocationMessage, name, messageFields(m.Message))
+			c, cancel := invocationContext(c)
+			defer cancel()
+			ctx.context = c
+			ctx.remainingHandlers = r.traceMessageHandlers(handlers)
+			r.observe(InvocationMessage, name, span, ctx.Next, func() bool { return ctx.failed })
+
+			var stopped interface{}
+			if n := len(ctx.remainingHandlers); n != 0 {
+				stopped = handlers[len(handlers)-n-1]
+			}
+			logDispatch(l, path, start, stopped, messageFields(m.Message))
+		})
 	}
 }
 
+// invocationContext derives the context of an invocation from the context of its span.
+// It is cancelled once the dispatch finishes, so listeners bound to it (e.g. collectors) are cleaned up.
+func invocationContext(c context.Context) (context.Context, context.CancelFunc) {
+	return context.WithCancel(c)
+}
+
 // NewRouter constructs a router from a set of predefined commands.
 func NewRouter(initial []*Command) (r *Router) {
-	r = &Router{Commands: make(map[string]*Command, len(initial)), Syncer: BulkCommandSyncer{}}
+	r = &Router{
+		Commands:   make(map[string]*Command, len(initial)),
+		Components: make(map[string]ComponentHandler),
+		Syncer:     BulkCommandSyncer{},
+	}
 	for _, cmd := range initial {
 		r.Register(cmd)
 	}
@@ -132,3 +132,78 @@ func TestRouter_Count(t *testing.T) {
 	assert.Len(t, router.Commands, len(commandList))
 	assert.Equal(t, len(commandList), router.Count())
 }
+
+func TestRouter_Order(t *testing.T) {
+	r := disgolf.NewRouter([]*disgolf.Command{{Name: "c"}, {Name: "a"}, {Name: "b"}})
+	r.Unregister("a")
+	r.Register(&disgolf.Command{Name: "a"})
+	r.Commands["0"] = &disgolf.Command{Name: "0"}
+
+	var names []string
+	for _, cmd := range r.List() {
+		names = append(names, cmd.Name)
+	}
+	assert.Equal(t, []string{"c", "b", "a", "0"}, names)
+
+	options := (&disgolf.Command{Name: "root", SubCommands: r}).ApplicationCommand().Options
+	names = nil
+	for _, opt := range options {
+		names = append(names, opt.Name)
+	}
+	assert.Equal(t, []string{"c", "b", "a", "0"}, names)
+}
+
+func TestRouter_ContextMenu(t *testing.T) {
+	var target *discordgo.Member
+	var message *discordgo.Message
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name:        "Profile",
+		Description: "ignored",
+		Type:        discordgo.UserApplicationCommand,
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			target = ctx.TargetMember()
+			message = ctx.TargetMessage()
+		}),
+	}})
+	assert.NoError(t, r.Validate())
+	assert.Empty(t, r.Get("Profile").ApplicationCommand().Description)
+
+	r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "guild",
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name:     "Profile",
+			TargetID: "42",
+			Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+				Users:   map[string]*discordgo.User{"42": {ID: "42", Username: "target"}},
+				Members: map[string]*discordgo.Member{"42": {Nick: "nick"}},
+			},
+		},
+	}})
+	if assert.NotNil(t, target) {
+		assert.Equal(t, "nick", target.Nick)
+		assert.Equal(t, "target", target.User.Username)
+		assert.Equal(t, "guild", target.GuildID)
+	}
+	assert.Nil(t, message)
+
+	r.Register(&disgolf.Command{
+		Name:    "Quote",
+		Type:    discordgo.MessageApplicationCommand,
+		Options: []*discordgo.ApplicationCommandOption{{Name: "text", Type: discordgo.ApplicationCommandOptionString}},
+	})
+	assert.ErrorIs(t, r.Validate(), disgolf.ErrContextMenuOptions)
+	assert.Empty(t, r.Get("Quote").ApplicationCommand().Options)
+	r.Unregister("Quote")
+
+	r.Register(&disgolf.Command{
+		Name: "settings",
+		SubCommands: disgolf.NewRouter([]*disgolf.Command{{
+			Name: "privacy",
+			SubCommands: disgolf.NewRouter([]*disgolf.Command{{
+				Name: "Reset",
+			}}),
+		}}),
+	})
+	assert.EqualError(t, r.Validate(), `command "settings": command "privacy": name "Reset" must be lowercase`)
+}
@@ -0,0 +1,220 @@
+package disgolf
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Tracer starts spans. It is designed to be a thin adapter over OpenTelemetry tracers:
+// the span must be stored in the returned context, so spans started from it (e.g. by database clients) are nested under it.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced operation.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	// SetError marks the span as failed.
+	SetError(err error)
+	End()
+}
+
+// Span attributes set by the router.
+const (
+	AttributeKind        = "disgolf.kind"
+	AttributeCommand     = "disgolf.command"
+	AttributeOutcome     = "disgolf.outcome"
+	AttributeHandler     = "disgolf.handler"
+	AttributeGuild       = "discord.guild_id"
+	AttributeChannel     = "discord.channel_id"
+	AttributeUser        = "discord.user_id"
+	AttributeInteraction = "discord.interaction_id"
+	AttributeMessage     = "discord.message_id"
+)
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(string, interface{}) {}
+func (nopSpan) SetError(error)                   {}
+func (nopSpan) End()                             {}
+
+// startSpan starts the root span of the invocation under the base context.
+// If the router has no tracer, the base context and a no-op span are returned.
+func (r *Router) startSpan(base context.Context, kind InvocationKind, command string, fields []interface{}) (context.Context, Span) {
+	if r.Tracer == nil {
+		return base, nopSpan{}
+	}
+
+	c, span := r.Tracer.Start(base, string(kind)+" "+command)
+	span.SetAttribute(AttributeKind, string(kind))
+	span.SetAttribute(AttributeCommand, command)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if key, ok := spanAttributes[fields[i].(string)]; ok {
+			span.SetAttribute(key, fields[i+1])
+		}
+	}
+	return c, span
+}
+
+// spanAttributes maps log fields to span attributes.
+var spanAttributes = map[string]string{
+	"guild":       AttributeGuild,
+	"channel":     AttributeChannel,
+	"user":        AttributeUser,
+	"interaction": AttributeInteraction,
+	"message":     AttributeMessage,
+}
+
+var (
+	errResponseFailed = errors.New("failed to respond")
+	errHandlerPanic   = errors.New("handler panicked")
+)
+
+// tracedHandler runs the handler in a child span of the invocation span.
+type tracedHandler struct {
+	tracer  Tracer
+	name    string
+	handler interface{}
+}
+
+func (h tracedHandler) start(inv Invocation) func() {
+	parent := inv.Context()
+	c, span := h.tracer.Start(parent, h.name)
+	span.SetAttribute(AttributeHandler, handlerName(h.handler))
+	inv.SetContext(c)
+	return func() {
+		span.End()
+		inv.SetContext(parent)
+	}
+}
+
+func (h tracedHandler) HandleCommand(ctx *Ctx) {
+	defer h.start(ctx)()
+	h.handler.(Handler).HandleCommand(ctx)
+}
+
+func (h tracedHandler) HandleMessageCommand(ctx *MessageCtx) {
+	defer h.start(ctx)()
+	h.handler.(MessageHandler).HandleMessageCommand(ctx)
+}
+
+func tracedHandlerName(i, n int) string {
+	if i == n-1 {
+		return "handler"
+	}
+	return "middleware"
+}
+
+// traceHandlers wraps the handlers into child spans. The last handler is the command handler, others are middlewares.
+func (r *Router) traceHandlers(handlers []Handler) []Handler {
+	if r.Tracer == nil {
+		return handlers
+	}
+	traced := make([]Handler, len(handlers))
+	for i, h := range handlers {
+		traced[i] = tracedHandler{tracer: r.Tracer, name: tracedHandlerName(i, len(handlers)), handler: h}
+	}
+	return traced
+}
+
+// traceMessageHandlers is traceHandlers for message handlers.
+func (r *Router) traceMessageHandlers(handlers []MessageHandler) []MessageHandler {
+	if r.Tracer == nil {
+		return handlers
+	}
+	traced := make([]MessageHandler, len(handlers))
+	for i, h := range handlers {
+		traced[i] = tracedHandler{tracer: r.Tracer, name: tracedHandlerName(i, len(handlers)), handler: h}
+	}
+	return traced
+}
+
+// MemoryTracer is a Tracer, which keeps all the spans in memory. It is useful for testing.
+type MemoryTracer struct {
+	mtx    sync.Mutex
+	spans  []*RecordedSpan
+	lastID uint64
+}
+
+type recordedSpanKey struct{}
+
+// RecordedSpan is a span recorded by MemoryTracer.
+type RecordedSpan struct {
+	ID uint64
+	// ParentID is the id of the parent span, or zero for root spans.
+	ParentID   uint64
+	Name       string
+	Attributes map[string]interface{}
+	Err        error
+	StartTime  time.Time
+	// EndTime is zero until the span is ended.
+	EndTime time.Time
+
+	tracer *MemoryTracer
+}
+
+// Start implements Tracer interface.
+func (t *MemoryTracer) Start(c context.Context, name string) (context.Context, Span) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.lastID++
+	span := &RecordedSpan{
+		ID:         t.lastID,
+		Name:       name,
+		Attributes: make(map[string]interface{}),
+		StartTime:  time.Now(),
+		tracer:     t,
+	}
+	if parent, ok := c.Value(recordedSpanKey{}).(*RecordedSpan); ok {
+		span.ParentID = parent.ID
+	}
+	t.spans = append(t.spans, span)
+	return context.WithValue(c, recordedSpanKey{}, span), span
+}
+
+// Spans returns copies of all recorded spans in the order they were started.
+func (t *MemoryTracer) Spans() []RecordedSpan {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	spans := make([]RecordedSpan, len(t.spans))
+	for i, span := range t.spans {
+		spans[i] = *span
+		spans[i].Attributes = make(map[string]interface{}, len(span.Attributes))
+		for k, v := range span.Attributes {
+			spans[i].Attributes[k] = v
+		}
+	}
+	return spans
+}
+
+// Reset removes all recorded spans.
+func (t *MemoryTracer) Reset() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.spans = nil
+}
+
+// SetAttribute implements Span interface.
+func (s *RecordedSpan) SetAttribute(key string, value interface{}) {
+	s.tracer.mtx.Lock()
+	defer s.tracer.mtx.Unlock()
+	s.Attributes[key] = value
+}
+
+// SetError implements Span interface.
+func (s *RecordedSpan) SetError(err error) {
+	s.tracer.mtx.Lock()
+	defer s.tracer.mtx.Unlock()
+	s.Err = err
+}
+
+// End implements Span interface.
+func (s *RecordedSpan) End() {
+	s.tracer.mtx.Lock()
+	defer s.tracer.mtx.Unlock()
+	s.EndTime = time.Now()
+}
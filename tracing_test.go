@@ -0,0 +1,120 @@
+package disgolf_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_Tracer(t *testing.T) {
+	tracer := &disgolf.MemoryTracer{}
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name: "parent",
+		SubCommands: disgolf.NewRouter([]*disgolf.Command{{
+			Name:        "child",
+			Middlewares: []disgolf.Handler{disgolf.HandlerFunc(callNext)},
+			Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+				_, span := tracer.Start(ctx.Context(), "query")
+				span.End()
+			}),
+		}}),
+	}})
+	r.Tracer = tracer
+
+	r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:      "interaction",
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "guild",
+		Member:  &discordgo.Member{User: &discordgo.User{ID: "user"}},
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: "parent",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "child", Type: discordgo.ApplicationCommandOptionSubCommand},
+			},
+		},
+	}})
+
+	spans := tracer.Spans()
+	if !assert.Len(t, spans, 4) {
+		return
+	}
+	root, middleware, handler, query := spans[0], spans[1], spans[2], spans[3]
+
+	assert.Equal(t, "slash parent child", root.Name)
+	assert.Zero(t, root.ParentID)
+	assert.Equal(t, "parent child", root.Attributes[disgolf.AttributeCommand])
+	assert.Equal(t, "guild", root.Attributes[disgolf.AttributeGuild])
+	assert.Equal(t, "user", root.Attributes[disgolf.AttributeUser])
+	assert.Equal(t, "ok", root.Attributes[disgolf.AttributeOutcome])
+	assert.NoError(t, root.Err)
+
+	assert.Equal(t, "middleware", middleware.Name)
+	assert.Equal(t, root.ID, middleware.ParentID)
+	assert.Equal(t, "handler", handler.Name)
+	assert.Equal(t, middleware.ID, handler.ParentID)
+	assert.Equal(t, "github.com/FedorLap2006/disgolf_test.callNext", middleware.Attributes[disgolf.AttributeHandler])
+	assert.Equal(t, "github.com/FedorLap2006/disgolf_test.TestRouter_Tracer.func1", handler.Attributes[disgolf.AttributeHandler])
+	assert.Equal(t, handler.ID, query.ParentID)
+
+	for _, span := range spans {
+		assert.False(t, span.EndTime.IsZero(), span.Name)
+	}
+}
+
+func callNext(ctx *disgolf.Ctx) { ctx.Next() }
+
+func TestRouter_TracerPanic(t *testing.T) {
+	tracer := &disgolf.MemoryTracer{}
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name:    "crash",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { panic("crash") }),
+	}})
+	r.Tracer = tracer
+
+	assert.Panics(t, func() {
+		r.HandleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{Name: "crash"},
+		}})
+	})
+
+	spans := tracer.Spans()
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, "panic", spans[0].Attributes[disgolf.AttributeOutcome])
+		assert.Error(t, spans[0].Err)
+		assert.False(t, spans[0].EndTime.IsZero())
+	}
+}
+
+func TestInteractionServer_Tracer(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+	tracer := &disgolf.MemoryTracer{}
+	r := disgolf.NewRouter([]*disgolf.Command{{
+		Name:    "ping",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) { _ = ctx.ReplyText("pong") }),
+	}})
+	r.Tracer = tracer
+	srv, err := disgolf.NewInteractionServer(r, &discordgo.Session{}, hex.EncodeToString(public))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c, request := tracer.Start(context.Background(), "POST /interactions")
+	serve(t, srv, signedRequest(t, private, `{"type":2,"data":{"name":"ping"}}`).WithContext(c))
+	request.End()
+
+	spans := tracer.Spans()
+	if assert.True(t, len(spans) > 1) {
+		assert.Equal(t, "slash ping", spans[1].Name)
+		assert.Equal(t, spans[0].ID, spans[1].ParentID)
+	}
+}
@@ -0,0 +1,261 @@
+package disgolf
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultViewTimeout is the time a view handles interactions, if its timeout is not specified.
+const DefaultViewTimeout = 5 * time.Minute
+
+// ViewCallback is called, when a user interacts with a component of a view.
+type ViewCallback func(ctx *ViewCtx)
+
+// ViewCtx is a context provided to view callbacks.
+type ViewCtx struct {
+	*ComponentCtx
+	View *View
+}
+
+// Update responds to the interaction with the current state of the view (content, embeds and components).
+// If the interaction was already responded to, the message is edited instead.
+func (ctx *ViewCtx) Update() error {
+	if ctx.Responded() {
+		return ctx.View.Update()
+	}
+	return ctx.Respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: ctx.View.data(),
+	})
+}
+
+// Stop stops the view. See View.Stop.
+func (ctx *ViewCtx) Stop() {
+	ctx.View.Stop()
+}
+
+// ViewComponent is a component of a view bound to a callback.
+type ViewComponent struct {
+	// Component is either discordgo.Button or discordgo.SelectMenu. Its custom id is generated by the view.
+	Component discordgo.MessageComponent
+	// Callback is called, when a user interacts with the component. Link buttons do not need it.
+	Callback ViewCallback
+
+	id string
+}
+
+// ViewButton binds the button to the callback.
+func ViewButton(button discordgo.Button, callback ViewCallback) *ViewComponent {
+	return &ViewComponent{Component: button, Callback: callback}
+}
+
+// ViewSelectMenu binds the select menu to the callback. Selected values are available in ctx.Data.Values.
+func ViewSelectMenu(menu discordgo.SelectMenu, callback ViewCallback) *ViewComponent {
+	return &ViewComponent{Component: menu, Callback: callback}
+}
+
+func (c *ViewComponent) render(m *interactiveMessage) discordgo.MessageComponent {
+	switch component := c.Component.(type) {
+	case discordgo.Button:
+		if component.Style != discordgo.LinkButton {
+			component.CustomID = m.customID(c.id)
+		}
+		return component
+	case discordgo.SelectMenu:
+		component.CustomID = m.customID(c.id)
+		return component
+	}
+	return c.Component
+}
+
+// View is a message with components bound to callbacks. Custom ids of the components are generated by the view,
+// and the interactions with them are dispatched to the callbacks, so no component handlers need to be registered.
+//
+// The view handles interactions until the timeout expires, or Stop is called. After that, its components are disabled.
+// Callbacks are called sequentially, and can modify the view and show the changes with ViewCtx.Update.
+// Update and Stop can be called from any goroutine, but the fields of the view must only be modified in callbacks.
+type View struct {
+	Content string
+	Embeds  []*discordgo.MessageEmbed
+	// State is arbitrary data of the view, e.g. a counter or a selected item. Callbacks can access it through ctx.View.State.
+	State interface{}
+
+	// AuthorOnly restricts the interactions to the author of the invocation. Other users receive an ephemeral notice.
+	AuthorOnly bool
+	// Timeout is the time the view handles interactions. Defaults to DefaultViewTimeout.
+	Timeout time.Duration
+	// OnStop is called after the view has stopped and its components were disabled.
+	// The reason is ErrCollectorTimeout or ErrCollectorStopped.
+	OnStop func(v *View, reason error)
+	// OnError is called, when the message of the view cannot be updated.
+	OnError func(err error)
+
+	rows    [][]*ViewComponent
+	lastID  int
+	message *interactiveMessage
+
+	mtx       sync.Mutex
+	collector *ComponentCollector
+	stopped   bool
+	done      chan struct{}
+}
+
+// AddRow adds a row of components to the view. A message can have up to 5 rows.
+func (v *View) AddRow(components ...*ViewComponent) *View {
+	for _, c := range components {
+		v.lastID++
+		c.id = strconv.Itoa(v.lastID)
+	}
+	v.rows = append(v.rows, components)
+	return v
+}
+
+// ClearRows removes all the components of the view.
+func (v *View) ClearRows() {
+	v.rows = nil
+}
+
+// Send sends the view in reply to the invocation, and starts handling the interactions in background.
+// The view does not depend on the context of the invocation, it lasts until the timeout expires or Stop is called.
+func (v *View) Send(inv Invocation) error {
+	m, err := newInteractiveMessage(inv, "view")
+	if err != nil {
+		return err
+	}
+	v.message = m
+
+	timeout := v.Timeout
+	if timeout == 0 {
+		timeout = DefaultViewTimeout
+	}
+	// NOTE: the context of the invocation is cancelled once the handler returns.
+	// The collector is registered before the message is sent, so no click can reach the component handlers of the router.
+	c, cancel := context.WithCancel(context.Background())
+	collector := m.collect(c, ComponentCollectorConfig{Timeout: timeout})
+	v.mtx.Lock()
+	v.collector = collector
+	if v.done == nil || v.stopped {
+		v.done = make(chan struct{})
+	}
+	v.stopped = false
+	done := v.done
+	v.mtx.Unlock()
+
+	if err = m.send(v.data()); err != nil {
+		collector.Stop()
+		cancel()
+		v.mtx.Lock()
+		v.stopped = true
+		v.mtx.Unlock()
+		close(done)
+		return err
+	}
+	go func() {
+		defer cancel()
+		v.run(done)
+	}()
+	return nil
+}
+
+// Update edits the message of the view to reflect its current state.
+func (v *View) Update() error {
+	return v.message.edit(v.data())
+}
+
+// Stop stops handling the interactions. Components of the view are disabled.
+func (v *View) Stop() {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if v.collector != nil {
+		v.collector.Stop()
+	}
+}
+
+// Done returns a channel, which is closed once the sent view has stopped, or could not be sent.
+// It can be called before Send.
+func (v *View) Done() <-chan struct{} {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if v.done == nil {
+		v.done = make(chan struct{})
+	}
+	return v.done
+}
+
+func (v *View) reportError(err error) {
+	if v.OnError != nil {
+		v.OnError(err)
+	}
+}
+
+func (v *View) run(done chan struct{}) {
+	var reason error
+	for {
+		ctx, err := v.collector.Next()
+		if err != nil {
+			reason = err
+			break
+		}
+		if v.AuthorOnly && v.message.rejectForeignUser(ctx) {
+			continue
+		}
+
+		if c := v.component(v.message.action(ctx)); c != nil && c.Callback != nil {
+			c.Callback(&ViewCtx{ComponentCtx: ctx, View: v})
+		}
+		if !ctx.Responded() {
+			if err = ctx.Respond(&discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate}); err != nil {
+				v.reportError(err)
+			}
+		}
+	}
+
+	v.mtx.Lock()
+	v.stopped = true
+	v.mtx.Unlock()
+	if err := v.Update(); err != nil {
+		v.reportError(err)
+	}
+	if v.OnStop != nil {
+		v.OnStop(v, reason)
+	}
+	close(done)
+}
+
+// component finds the component by its id. Interactions with removed components are ignored.
+func (v *View) component(id string) *ViewComponent {
+	for _, row := range v.rows {
+		for _, c := range row {
+			if c.id == id {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+func (v *View) data() *discordgo.InteractionResponseData {
+	embeds := v.Embeds
+	if embeds == nil {
+		embeds = []*discordgo.MessageEmbed{}
+	}
+	components := make([]discordgo.MessageComponent, 0, len(v.rows))
+	for _, row := range v.rows {
+		rendered := make([]discordgo.MessageComponent, len(row))
+		for i, c := range row {
+			rendered[i] = c.render(v.message)
+		}
+		components = append(components, discordgo.ActionsRow{Components: rendered})
+	}
+	v.mtx.Lock()
+	stopped := v.stopped
+	v.mtx.Unlock()
+	if stopped {
+		components = disableComponents(components)
+	}
+	return &discordgo.InteractionResponseData{Content: v.Content, Embeds: embeds, Components: components}
+}
@@ -0,0 +1,132 @@
+package disgolf_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FedorLap2006/disgolf"
+	"github.com/FedorLap2006/disgolf/disgolftest"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestView(t *testing.T) {
+	var reason error
+	view := &disgolf.View{Content: "Count: 0", State: 0, OnStop: func(v *disgolf.View, err error) { reason = err }}
+	increment := disgolf.ViewButton(discordgo.Button{Label: "+1"}, func(ctx *disgolf.ViewCtx) {
+		ctx.View.State = ctx.View.State.(int) + 1
+		ctx.View.Content = fmt.Sprint("Count: ", ctx.View.State)
+		assert.NoError(t, ctx.Update())
+	})
+	view.AddRow(
+		increment,
+		disgolf.ViewButton(discordgo.Button{Label: "Stop", Style: discordgo.DangerButton}, func(ctx *disgolf.ViewCtx) { ctx.Stop() }),
+		disgolf.ViewButton(discordgo.Button{Label: "Docs", Style: discordgo.LinkButton, URL: "https://example.com"}, nil),
+	)
+
+	done := view.Done()
+	h := disgolftest.New(disgolf.NewRouter([]*disgolf.Command{{
+		Name: "counter",
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			assert.NoError(t, view.Send(ctx))
+		}),
+	}}))
+
+	i := disgolftest.CommandInteraction("counter")
+	i.ID = "300"
+	if responses := decodePayloads(t, h.Dispatch(i).Filter(http.MethodPost, "interactions/*/*/callback")); assert.Len(t, responses, 1) {
+		assert.Equal(t, "Count: 0", responses[0].Data.Content)
+	}
+
+	h.Dispatch(disgolftest.ComponentInteraction("view:300:1"))
+	h.Dispatch(disgolftest.ComponentInteraction("view:300:1"))
+	h.Dispatch(disgolftest.ComponentInteraction("view:300:2"))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("view has not stopped")
+	}
+
+	responses := decodePayloads(t, h.Transport.Calls().Filter(http.MethodPost, "interactions/*/*/callback"))
+	if assert.Len(t, responses, 4) {
+		assert.Equal(t, "Count: 1", responses[1].Data.Content)
+		assert.Equal(t, "Count: 2", responses[2].Data.Content)
+		assert.Equal(t, int(discordgo.InteractionResponseDeferredMessageUpdate), responses[3].Type)
+	}
+	if edits := decodePayloads(t, h.Transport.Calls().Filter(http.MethodPatch, "webhooks/*/*/messages/@original")); assert.Len(t, edits, 1) {
+		buttons := edits[0].Components[0].Components
+		assert.Equal(t, "view:300:1", buttons[0].CustomID)
+		assert.True(t, buttons[0].Disabled)
+		assert.True(t, buttons[1].Disabled)
+		assert.False(t, buttons[2].Disabled)
+		assert.Empty(t, buttons[2].CustomID)
+	}
+	assert.Equal(t, disgolf.ErrCollectorStopped, reason)
+	assert.Equal(t, 2, view.State)
+}
+
+func TestView_Timeout(t *testing.T) {
+	reasons := make(chan error, 1)
+	view := &disgolf.View{
+		Content: "Pick",
+		Timeout: 10 * time.Millisecond,
+		OnStop:  func(v *disgolf.View, err error) { reasons <- err },
+	}
+	view.AddRow(disgolf.ViewSelectMenu(discordgo.SelectMenu{Options: []discordgo.SelectMenuOption{{Label: "A", Value: "a"}}}, nil))
+
+	h := disgolftest.New(disgolf.NewRouter([]*disgolf.Command{{
+		Name: "pick",
+		MessageHandler: disgolf.MessageHandlerFunc(func(ctx *disgolf.MessageCtx) {
+			assert.NoError(t, view.Send(ctx))
+		}),
+	}}))
+	h.Send("!pick")
+
+	select {
+	case err := <-reasons:
+		assert.Equal(t, disgolf.ErrCollectorTimeout, err)
+	case <-time.After(time.Second):
+		t.Fatal("view has not timed out")
+	}
+	if edits := decodePayloads(t, h.Transport.Calls().Filter(http.MethodPatch, "channels/*/messages/*")); assert.Len(t, edits, 1) {
+		assert.Equal(t, "Pick", edits[0].Content)
+		assert.True(t, edits[0].Components[0].Components[0].Disabled)
+	}
+}
+
+func TestView_Concurrency(t *testing.T) {
+	view := &disgolf.View{Content: "Running"}
+	view.AddRow(disgolf.ViewButton(discordgo.Button{Label: "Stop"}, func(ctx *disgolf.ViewCtx) { ctx.Stop() }))
+
+	h := disgolftest.New(disgolf.NewRouter([]*disgolf.Command{{
+		Name:        "run",
+		Concurrency: &disgolf.ConcurrencyLimit{},
+		Handler: disgolf.HandlerFunc(func(ctx *disgolf.Ctx) {
+			assert.NoError(t, view.Send(ctx))
+		}),
+	}}))
+	i := disgolftest.CommandInteraction("run")
+	i.ID = "400"
+	h.Dispatch(i)
+
+	select {
+	case <-view.Done():
+		t.Fatal("view has stopped with the handler")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	updated := make(chan struct{})
+	go func() {
+		defer close(updated)
+		assert.NoError(t, view.Update())
+	}()
+	h.Dispatch(disgolftest.ComponentInteraction("view:400:1"))
+	<-updated
+	select {
+	case <-view.Done():
+	case <-time.After(time.Second):
+		t.Fatal("view has not stopped")
+	}
+}